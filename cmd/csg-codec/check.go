@@ -0,0 +1,66 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/xrstf/rct/csg"
+)
+
+func checkCommand(args []string) {
+	flags := newFlagSet("check", "INDEX DATA")
+
+	parseArgs(flags, args, 2)
+
+	index, graphics, data, err := openFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer data.Close()
+
+	damaged := make([]csg.ExtractResult, 0)
+	checked := 0
+
+	for result := range graphics.ExtractAll(context.Background(), index, csg.ExtractOptions{}) {
+		checked++
+
+		if result.Err != nil {
+			damaged = append(damaged, result)
+		}
+	}
+
+	for number, element := range index.Elements {
+		if element.Type != csg.PaletteType {
+			continue
+		}
+
+		checked++
+
+		if _, err := graphics.ExtractPalette(element); err != nil {
+			if extractErr, ok := err.(*csg.ExtractError); ok {
+				extractErr.Number = number
+			}
+
+			damaged = append(damaged, csg.ExtractResult{Number: number, Element: element, Err: err})
+		}
+	}
+
+	sort.Slice(damaged, func(i, j int) bool {
+		return damaged[i].Number < damaged[j].Number
+	})
+
+	for _, result := range damaged {
+		fmt.Println(result.Err)
+	}
+
+	fmt.Printf("Checked %d elements, %d are damaged.\n", checked, len(damaged))
+
+	if len(damaged) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/xrstf/rct/csg"
+)
+
+func diffCommand(args []string) {
+	flags := newFlagSet("diff", "OLD_INDEX OLD_DATA NEW_INDEX NEW_DATA")
+	outDir := flags.String("png", "", "render side-by-side PNGs of changed sprites into this directory")
+	paletteNum := flags.Int("palette", -1, "index element to use as the palette for rendering (default: the game palette)")
+
+	parseArgs(flags, args, 4)
+
+	oldIndex, oldGraphics, oldData, err := openFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer oldData.Close()
+
+	newIndex, newGraphics, newData, err := openFiles(flags.Arg(2), flags.Arg(3))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer newData.Close()
+
+	changes := csg.Diff(oldIndex, oldGraphics, newIndex, newGraphics)
+
+	for _, change := range changes {
+		line := fmt.Sprintf("%6d  %s", change.Number, change.Changes)
+
+		if change.Old != nil && change.New != nil && change.Changes&(csg.SpriteResized|csg.SpriteReanchored) > 0 {
+			line += fmt.Sprintf(" (%dx%d %+d/%+d -> %dx%d %+d/%+d)",
+				change.Old.Width, change.Old.Height, change.Old.XOffset, change.Old.YOffset,
+				change.New.Width, change.New.Height, change.New.XOffset, change.New.YOffset)
+		}
+
+		if change.Err != nil {
+			line += ": " + change.Err.Error()
+		}
+
+		fmt.Println(line)
+	}
+
+	fmt.Printf("%d sprites changed.\n", len(changes))
+
+	if *outDir == "" {
+		return
+	}
+
+	palette, err := loadPalette(newIndex, newGraphics, *paletteNum)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	remapSet, _ := csg.NewRemapSet(0, 0, 0)
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, change := range changes {
+		img := change.SideBySide(palette, remapSet)
+		if img == nil {
+			continue
+		}
+
+		if err := writePNGFile(filepath.Join(*outDir, fmt.Sprintf("%05d.png", change.Number)), img); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/xrstf/rct/csg"
+)
+
+func extractCommand(args []string) {
+	flags := newFlagSet("extract", "INDEX DATA [RANGES...]")
+	outDir := flags.String("out", ".", "directory to write the PNG files to")
+	paletteNum := flags.Int("palette", -1, "index element to use as the palette (default: the game palette)")
+	remap := flags.String("remap", "0,0,0", "remap colors to apply, as three comma separated numbers")
+	scale := flags.Int("scale", 1, "integer factor to scale sprites up by")
+	trim := flags.Bool("trim", false, "crop sprites to their non-transparent pixels")
+	workers := flags.Int("workers", 0, "number of concurrent decoders (default: number of CPUs)")
+
+	parseArgs(flags, args, 2)
+
+	ranges, err := parseRanges(flags.Args()[2:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	combination, err := parseRemap(*remap)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	remapping, err := combination.RemapSet()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	index, graphics, data, err := openFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer data.Close()
+
+	palette, err := loadPalette(index, graphics, *paletteNum)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	opts := csg.ExtractOptions{
+		Workers: *workers,
+		Filter: func(number int, element csg.IndexStruct) bool {
+			return ranges.contains(number)
+		},
+	}
+
+	written := 0
+	failed := 0
+
+	for result := range graphics.ExtractAll(context.Background(), index, opts) {
+		if result.Err != nil {
+			log.Println(result.Err)
+			failed++
+			continue
+		}
+
+		bitmap := result.Bitmap
+		element := result.Element
+
+		if *trim {
+			bitmap, element = bitmap.Trim(element)
+		}
+
+		if *scale > 1 {
+			bitmap, element, err = bitmap.Scale(element, *scale)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if bitmap.Width == 0 || bitmap.Height == 0 {
+			continue
+		}
+
+		filename := filepath.Join(*outDir, fmt.Sprintf("%05d.png", result.Number))
+
+		if err := writePNGFile(filename, bitmap.Paletted(palette, remapping)); err != nil {
+			log.Fatal(err)
+		}
+
+		written++
+	}
+
+	fmt.Printf("Wrote %d sprites to %s.\n", written, *outDir)
+
+	if failed > 0 {
+		log.Fatalf("%d sprites could not be decoded.", failed)
+	}
+}
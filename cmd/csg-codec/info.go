@@ -0,0 +1,52 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/xrstf/rct/csg"
+)
+
+func infoCommand(args []string) {
+	flags := newFlagSet("info", "INDEX DATA")
+
+	parseArgs(flags, args, 2)
+
+	index, graphics, data, err := openFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer data.Close()
+
+	counts := make(map[csg.ElementType]int)
+	for _, element := range index.Elements {
+		counts[element.Type]++
+	}
+
+	fmt.Printf("Elements.........: %d\n", len(index.Elements))
+	fmt.Printf("Direct bitmaps...: %d\n", counts[csg.DirectBitmapType])
+	fmt.Printf("Compacted bitmaps: %d\n", counts[csg.CompactedBitmapType])
+	fmt.Printf("Palettes.........: %d\n", counts[csg.PaletteType])
+	fmt.Printf("Unknown elements.: %d\n", len(index.Unknown()))
+	fmt.Printf("Data size........: %d bytes\n", graphics.Size())
+
+	edition, err := csg.CheckEdition(index, graphics)
+	fmt.Printf("Edition..........: %s\n", edition)
+
+	if err != nil {
+		fmt.Printf("Mismatch.........: %s\n", err)
+	}
+
+	_, missing, err := csg.BuildGamePalette(index, graphics)
+	if err != nil {
+		fmt.Printf("Game palette.....: %s\n", err)
+	} else {
+		fmt.Printf("Game palette.....: %d of 256 colors\n", 256-len(missing))
+	}
+
+	for _, warning := range index.Warnings {
+		fmt.Printf("Warning..........: %s\n", warning)
+	}
+}
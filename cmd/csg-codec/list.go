@@ -0,0 +1,85 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/xrstf/rct/csg"
+)
+
+var typeFilters = map[string]func(csg.IndexStruct) bool{
+	"bitmap":    isBitmap,
+	"direct":    func(e csg.IndexStruct) bool { return e.Type == csg.DirectBitmapType },
+	"compacted": func(e csg.IndexStruct) bool { return e.Type == csg.CompactedBitmapType },
+	"palette":   func(e csg.IndexStruct) bool { return e.Type == csg.PaletteType },
+	"unknown":   func(e csg.IndexStruct) bool { return !e.IsKnownType() },
+}
+
+func listCommand(args []string) {
+	flags := newFlagSet("list", "INDEX [RANGES...]")
+	types := flags.String("type", "", "only list these types, comma separated (bitmap, direct, compacted, palette, unknown)")
+
+	parseArgs(flags, args, 1)
+
+	filters := make([]func(csg.IndexStruct) bool, 0)
+
+	if *types != "" {
+		for _, name := range strings.Split(*types, ",") {
+			filter, exists := typeFilters[strings.TrimSpace(name)]
+			if !exists {
+				log.Fatalf("Unknown type %q.", name)
+			}
+
+			filters = append(filters, filter)
+		}
+	}
+
+	ranges, err := parseRanges(flags.Args()[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	index, err := csg.NewIndexDecoder().DecodeFile(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "number\ttype\tflags\twidth\theight\tx\ty\taddress\t")
+
+	for number, element := range index.Elements {
+		if !ranges.contains(number) || !matchesAny(element, filters) {
+			continue
+		}
+
+		fmt.Fprintf(table, "%d\t%s\t0x%02X\t%d\t%d\t%d\t%d\t0x%08X\t\n",
+			number, element.Type, element.Flags, element.Width, element.Height, element.XOffset, element.YOffset, element.StartAddress)
+	}
+
+	table.Flush()
+}
+
+func matchesAny(element csg.IndexStruct, filters []func(csg.IndexStruct) bool) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	for _, filter := range filters {
+		if filter(element) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,53 +3,217 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"image"
 	"image/png"
+	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/xrstf/rct/csg"
 )
 
+const usage = `Usage: csg-codec COMMAND [flags] INDEX DATA [...]
+
+INDEX is CSG1i.DAT, DATA is CSG1.DAT. Available commands:
+
+  list      print a table of all index elements
+  extract   write sprites as PNG files
+  palette   write all palettes as PNG files
+  info      print a summary of the file pair
+  check     decode every sprite and report the damaged ones
+  diff      compare two file pairs sprite by sprite
+  serve     browse all sprites in a web browser
+
+Run "csg-codec COMMAND -h" for the flags of each command.
+`
+
 func main() {
-	if len(os.Args) < 3 {
-		log.Fatal("No filenames given.")
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
 	}
 
-	file, err := os.Open(os.Args[1])
-	if err != nil {
-		log.Fatal(err)
+	commands := map[string]func([]string){
+		"list":    listCommand,
+		"extract": extractCommand,
+		"palette": paletteCommand,
+		"info":    infoCommand,
+		"check":   checkCommand,
+		"diff":    diffCommand,
+		"serve":   serveCommand,
+	}
+
+	command, exists := commands[os.Args[1]]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Unknown command %q.\n\n%s", os.Args[1], usage)
+		os.Exit(2)
 	}
 
-	idxDecoder := csg.NewIndexDecoder()
+	command(os.Args[2:])
+}
+
+// newFlagSet creates the flags for a command; args describes the positional
+// arguments for the usage message.
+func newFlagSet(command string, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: csg-codec %s [flags] %s\n", command, args)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+// parseArgs parses the flags and makes sure at least min positional arguments
+// are given.
+func parseArgs(flags *flag.FlagSet, args []string, min int) {
+	flags.Parse(args)
 
-	index, err := idxDecoder.DecodeFile(file)
+	if flags.NArg() < min {
+		flags.Usage()
+		os.Exit(2)
+	}
+}
+
+// openFiles decodes the index file and opens the data file for lazy reading.
+// The returned closer closes the data file.
+func openFiles(indexFile string, dataFile string) (csg.Index, *csg.Graphics, io.Closer, error) {
+	file, err := os.Open(indexFile)
 	if err != nil {
-		log.Fatal(err)
+		return csg.Index{}, nil, nil, err
 	}
+	defer file.Close()
 
-	file, err = os.Open(os.Args[2])
+	index, err := csg.NewIndexDecoder().DecodeFile(file)
 	if err != nil {
-		log.Fatal(err)
+		return csg.Index{}, nil, nil, err
 	}
 
-	graphics, err := csg.NewGraphics(file)
+	data, err := os.Open(dataFile)
 	if err != nil {
-		log.Fatal(err)
+		return csg.Index{}, nil, nil, err
 	}
 
-	bitmap, err := graphics.ExtractCompactedImage(index[61996])
-	palette, err := graphics.ExtractPalette(index[2024])
+	info, err := data.Stat()
+	if err != nil {
+		data.Close()
+		return csg.Index{}, nil, nil, err
+	}
 
-	out, err := os.Create("test.png")
+	graphics, err := csg.NewGraphicsFromReaderAt(data, info.Size())
 	if err != nil {
-		log.Fatal(err)
+		data.Close()
+		return csg.Index{}, nil, nil, err
 	}
 
-	remapSet, _ := csg.NewRemapSet(24, 30, 2)
+	return index, graphics, data, nil
+}
+
+// loadPalette extracts the given palette element, or assembles the full game
+// palette if number is negative.
+func loadPalette(index csg.Index, graphics *csg.Graphics, number int) (*csg.Palette, error) {
+	if number < 0 {
+		palette, _, err := csg.BuildGamePalette(index, graphics)
+		return palette, err
+	}
+
+	return csg.MergePalettes(index, graphics, []int{number})
+}
+
+// parseRemap parses a remap combination like "24,30,2".
+func parseRemap(value string) (csg.RemapCombination, error) {
+	combination := csg.RemapCombination{}
+	parts := strings.Split(value, ",")
+
+	if len(parts) != 3 {
+		return combination, fmt.Errorf("A remap combination must consist of three numbers, got %q.", value)
+	}
 
-	encoder := png.Encoder{png.BestCompression}
-	err = encoder.Encode(out, bitmap.ToImage(palette, remapSet))
+	for i, part := range parts {
+		number, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || number < 0 || number >= len(csg.RemapPalettes) {
+			return combination, fmt.Errorf("Invalid remap color %q.", part)
+		}
+
+		combination[i] = number
+	}
+
+	return combination, nil
+}
+
+// numberRanges is a set of sprite numbers given as "10-20,35,40-".
+type numberRanges [][2]int
+
+func parseRanges(values []string) (numberRanges, error) {
+	ranges := make(numberRanges, 0)
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			bounds := strings.SplitN(part, "-", 2)
+			from, err := strconv.Atoi(bounds[0])
+			if err != nil || from < 0 {
+				return nil, fmt.Errorf("Invalid range %q.", part)
+			}
+
+			to := from
+
+			if len(bounds) == 2 {
+				if bounds[1] == "" {
+					to = int(^uint(0) >> 1)
+				} else if to, err = strconv.Atoi(bounds[1]); err != nil || to < from {
+					return nil, fmt.Errorf("Invalid range %q.", part)
+				}
+			}
+
+			ranges = append(ranges, [2]int{from, to})
+		}
+	}
+
+	return ranges, nil
+}
+
+// contains returns true if the number is in one of the ranges. An empty set of
+// ranges contains every number.
+func (r numberRanges) contains(number int) bool {
+	if len(r) == 0 {
+		return true
+	}
+
+	for _, bounds := range r {
+		if number >= bounds[0] && number <= bounds[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBitmap(element csg.IndexStruct) bool {
+	return element.Type == csg.DirectBitmapType || element.Type == csg.CompactedBitmapType
+}
+
+func writePNGFile(filename string, img image.Image) error {
+	out, err := os.Create(filename)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(out, img); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
 }
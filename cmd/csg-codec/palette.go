@@ -0,0 +1,68 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/xrstf/rct/csg"
+)
+
+func paletteCommand(args []string) {
+	flags := newFlagSet("palette", "INDEX DATA [RANGES...]")
+	outDir := flags.String("out", ".", "directory to write the PNG files to")
+
+	parseArgs(flags, args, 2)
+
+	ranges, err := parseRanges(flags.Args()[2:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	index, graphics, data, err := openFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer data.Close()
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	gamePalette, missing, err := csg.BuildGamePalette(index, graphics)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := writePNGFile(filepath.Join(*outDir, "game.png"), gamePalette.ToImage()); err != nil {
+		log.Fatal(err)
+	}
+
+	if len(missing) > 0 {
+		fmt.Printf("The game palette does not cover %d colors: % X\n", len(missing), missing)
+	}
+
+	written := 1
+
+	for number, element := range index.Elements {
+		if element.Type != csg.PaletteType || !ranges.contains(number) {
+			continue
+		}
+
+		palette, err := graphics.ExtractPalette(element)
+		if err != nil {
+			log.Fatalf("Could not extract palette %d: %s", number, err)
+		}
+
+		if err := writePNGFile(filepath.Join(*outDir, fmt.Sprintf("palette-%05d.png", number)), palette.ToImage()); err != nil {
+			log.Fatal(err)
+		}
+
+		written++
+	}
+
+	fmt.Printf("Wrote %d palettes to %s.\n", written, *outDir)
+}
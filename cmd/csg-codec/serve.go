@@ -0,0 +1,346 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"image"
+	"image/png"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/xrstf/rct/csg"
+)
+
+type server struct {
+	index       csg.Index
+	graphics    *csg.Graphics
+	gamePalette *csg.Palette
+	palettes    []int // numbers of all palette elements
+	pageSize    int
+}
+
+// viewOptions are the rendering options shared by the gallery and the PNG endpoints.
+type viewOptions struct {
+	Remap   csg.RemapCombination
+	Scale   int
+	Palette int // -1 for the game palette
+}
+
+func serveCommand(args []string) {
+	flags := newFlagSet("serve", "INDEX DATA")
+	listen := flags.String("listen", "127.0.0.1:8080", "address to listen on")
+	pageSize := flags.Int("page-size", 100, "number of sprites per gallery page")
+	cacheSize := flags.Int("cache", 2048, "number of decoded sprites to keep in memory")
+
+	parseArgs(flags, args, 2)
+
+	index, graphics, data, err := openFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer data.Close()
+
+	graphics.SetCacheSize(*cacheSize)
+
+	gamePalette, _, err := csg.BuildGamePalette(index, graphics)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := &server{index, graphics, gamePalette, make([]int, 0), *pageSize}
+
+	for number, element := range index.Elements {
+		if element.Type == csg.PaletteType {
+			s.palettes = append(s.palettes, number)
+		}
+	}
+
+	if s.pageSize < 1 {
+		s.pageSize = 100
+	}
+
+	http.HandleFunc("/", s.gallery)
+	http.HandleFunc("/sprite/", s.sprite)
+	http.HandleFunc("/palette/", s.palette)
+
+	log.Printf("Serving %d sprites on http://%s/", len(index.Elements), *listen)
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
+
+func (s *server) gallery(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	opts, err := parseViewOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pages := (len(s.index.Elements) + s.pageSize - 1) / s.pageSize
+
+	if page < 0 {
+		page = 0
+	} else if page >= pages && pages > 0 {
+		page = pages - 1
+	}
+
+	type entry struct {
+		Number  int
+		Element csg.IndexStruct
+		IsImage bool
+	}
+
+	entries := make([]entry, 0, s.pageSize)
+
+	for number := page * s.pageSize; number < (page+1)*s.pageSize && number < len(s.index.Elements); number++ {
+		element := s.index.Elements[number]
+		isImage := element.Type == csg.DirectBitmapType || element.Type == csg.CompactedBitmapType || element.Type == csg.PaletteType
+
+		entries = append(entries, entry{number, element, isImage})
+	}
+
+	remaps := make([]string, len(csg.RemapPalettes))
+	for i, remap := range csg.RemapPalettes {
+		c := remap.DisplayColor
+		remaps[i] = fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+	}
+
+	data := map[string]interface{}{
+		"Entries":  entries,
+		"Page":     page,
+		"Pages":    pages,
+		"Options":  opts,
+		"Query":    template.URL(opts.query()),
+		"Palettes": s.palettes,
+		"Remaps":   remaps,
+		"Slots":    []int{0, 1, 2},
+		"Scales":   []int{1, 2, 3, 4, 6, 8},
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := galleryTemplate.Execute(w, data); err != nil {
+		log.Printf("Could not render gallery: %s", err)
+	}
+}
+
+// sprite serves /sprite/{n}.png
+func (s *server) sprite(w http.ResponseWriter, r *http.Request) {
+	number, ok := parseNumber(r.URL.Path, "/sprite/", ".png")
+	if !ok || number >= len(s.index.Elements) {
+		http.NotFound(w, r)
+		return
+	}
+
+	opts, err := parseViewOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	element := s.index.Elements[number]
+
+	if element.Type == csg.PaletteType {
+		palette, err := s.graphics.ExtractPalette(element)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writePNG(w, palette.ToImage())
+		return
+	}
+
+	bitmap, err := s.graphics.ExtractBitmapAt(s.index, number)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if opts.Scale > 1 {
+		bitmap, _, err = bitmap.Scale(element, opts.Scale)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	palette, err := s.resolvePalette(opts.Palette)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	remapping, err := opts.Remap.RemapSet()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writePNG(w, bitmap.Paletted(palette, remapping))
+}
+
+// palette serves /palette/{n}.png and /palette/game.png
+func (s *server) palette(w http.ResponseWriter, r *http.Request) {
+	number := -1
+
+	if r.URL.Path != "/palette/game.png" {
+		n, ok := parseNumber(r.URL.Path, "/palette/", ".png")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		number = n
+	}
+
+	palette, err := s.resolvePalette(number)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writePNG(w, palette.ToImage())
+}
+
+func (s *server) resolvePalette(number int) (*csg.Palette, error) {
+	if number < 0 {
+		return s.gamePalette, nil
+	}
+
+	if number >= len(s.index.Elements) || s.index.Elements[number].Type != csg.PaletteType {
+		return nil, fmt.Errorf("Element %d is not a palette.", number)
+	}
+
+	return s.graphics.ExtractPalette(s.index.Elements[number])
+}
+
+func parseViewOptions(query url.Values) (viewOptions, error) {
+	opts := viewOptions{Scale: 1, Palette: -1}
+
+	// the gallery's form submits the three remap colors separately
+	if query.Get("remap0") != "" {
+		query.Set("remap", strings.Join([]string{query.Get("remap0"), query.Get("remap1"), query.Get("remap2")}, ","))
+	}
+
+	if remap := query.Get("remap"); remap != "" {
+		combination, err := parseRemap(remap)
+		if err != nil {
+			return opts, err
+		}
+
+		opts.Remap = combination
+	}
+
+	if scale := query.Get("scale"); scale != "" {
+		value, err := strconv.Atoi(scale)
+		if err != nil || value < 1 || value > 16 {
+			return opts, fmt.Errorf("scale must be between 1 and 16, got %q.", scale)
+		}
+
+		opts.Scale = value
+	}
+
+	if palette := query.Get("palette"); palette != "" && palette != "game" {
+		value, err := strconv.Atoi(palette)
+		if err != nil {
+			return opts, fmt.Errorf("Invalid palette %q.", palette)
+		}
+
+		opts.Palette = value
+	}
+
+	return opts, nil
+}
+
+// query encodes the options for use in URLs.
+func (o viewOptions) query() string {
+	values := url.Values{}
+	values.Set("remap", fmt.Sprintf("%d,%d,%d", o.Remap[0], o.Remap[1], o.Remap[2]))
+	values.Set("scale", strconv.Itoa(o.Scale))
+
+	if o.Palette >= 0 {
+		values.Set("palette", strconv.Itoa(o.Palette))
+	}
+
+	return values.Encode()
+}
+
+func parseNumber(path string, prefix string, suffix string) (int, bool) {
+	if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, suffix) {
+		return 0, false
+	}
+
+	number, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, prefix), suffix))
+	if err != nil || number < 0 {
+		return 0, false
+	}
+
+	return number, true
+}
+
+func writePNG(w http.ResponseWriter, img image.Image) {
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "max-age=3600")
+
+	if err := png.Encode(w, img); err != nil {
+		log.Printf("Could not encode PNG: %s", err)
+	}
+}
+
+var galleryTemplate = template.Must(template.New("gallery").Funcs(template.FuncMap{
+	"add": func(a int, b int) int { return a + b },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>CSG sprites, page {{add .Page 1}} of {{.Pages}}</title>
+	<style>
+		body { font-family: sans-serif; background: #ddd; }
+		.sprite { display: inline-block; vertical-align: top; margin: 4px; padding: 4px; background: #fff; font-size: 11px; }
+		.sprite img { display: block; image-rendering: pixelated; max-width: 400px; }
+		.swatch { display: inline-block; width: 12px; height: 12px; }
+	</style>
+</head>
+<body>
+	<form method="get">
+		<input type="hidden" name="page" value="{{.Page}}">
+		{{range $slot := .Slots}}
+		<select name="remap{{$slot}}">
+			{{range $i, $color := $.Remaps}}<option value="{{$i}}" style="background: {{$color}}"{{if eq $i (index $.Options.Remap $slot)}} selected{{end}}>{{$i}}</option>{{end}}
+		</select>
+		{{end}}
+		<select name="palette">
+			<option value="game">game palette</option>
+			{{range .Palettes}}<option value="{{.}}"{{if eq . $.Options.Palette}} selected{{end}}>palette {{.}}</option>{{end}}
+		</select>
+		<select name="scale">
+			{{range .Scales}}<option value="{{.}}"{{if eq . $.Options.Scale}} selected{{end}}>{{.}}x</option>{{end}}
+		</select>
+		<button type="submit">apply</button>
+		<a href="/palette/game.png">show game palette</a>
+	</form>
+	<p>
+		{{if gt .Page 0}}<a href="?page={{add .Page -1}}&amp;{{.Query}}">previous</a>{{end}}
+		page {{add .Page 1}} of {{.Pages}}
+		{{if lt (add .Page 1) .Pages}}<a href="?page={{add .Page 1}}&amp;{{.Query}}">next</a>{{end}}
+	</p>
+	{{range .Entries}}
+	<div class="sprite">
+		{{if .IsImage}}<a href="/sprite/{{.Number}}.png?{{$.Query}}"><img src="/sprite/{{.Number}}.png?{{$.Query}}" alt="sprite {{.Number}}"></a>{{end}}
+		#{{.Number}} {{.Element.Type}}<br>
+		{{.Element.Width}}x{{.Element.Height}}, offset {{.Element.XOffset}}/{{.Element.YOffset}}
+	</div>
+	{{end}}
+</body>
+</html>
+`))
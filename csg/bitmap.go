@@ -3,6 +3,7 @@
 package csg
 
 import (
+	"errors"
 	"image"
 	"image/color"
 )
@@ -13,6 +14,86 @@ type Bitmap struct {
 	Pixels []byte
 }
 
+// Creates a new, fully transparent bitmap.
+func NewBitmap(width uint16, height uint16) *Bitmap {
+	return &Bitmap{width, height, make([]byte, int(width)*int(height))}
+}
+
+// pixel returns the palette index at (x,y), or 0 if the position is outside of
+// the bitmap.
+func (self *Bitmap) pixel(x int, y int) byte {
+	if x < 0 || y < 0 || x >= int(self.Width) || y >= int(self.Height) {
+		return 0
+	}
+
+	return self.Pixels[y*int(self.Width)+x]
+}
+
+func (self *Bitmap) clone() *Bitmap {
+	pixels := make([]byte, len(self.Pixels))
+	copy(pixels, self.Pixels)
+
+	return &Bitmap{self.Width, self.Height, pixels}
+}
+
+// Draws src onto the bitmap with its top left corner at (x,y). Transparent pixels
+// of src are skipped. If table is not nil, it is applied to src's pixels first,
+// which is how the game draws ghost previews.
+func (self *Bitmap) Draw(src *Bitmap, x int, y int, table *LookupTable) {
+	self.composite(src, x, y, func(dst byte, key byte) byte {
+		if table != nil {
+			return table[key]
+		}
+
+		return key
+	})
+}
+
+// Applies the table to every pixel of the bitmap that is covered by a
+// non-transparent pixel of mask, placed at (x,y). This is how the game draws
+// shadows, glass and water: the mask only decides where, the colors of the
+// underlying bitmap are darkened or tinted.
+func (self *Bitmap) Tint(mask *Bitmap, x int, y int, table *LookupTable) error {
+	if table == nil {
+		return errors.New("Tinting requires a lookup table.")
+	}
+
+	self.composite(mask, x, y, func(dst byte, key byte) byte {
+		return table[dst]
+	})
+
+	return nil
+}
+
+func (self *Bitmap) composite(src *Bitmap, x int, y int, combine func(byte, byte) byte) {
+	width := int(self.Width)
+	height := int(self.Height)
+	srcWidth := int(src.Width)
+	srcHeight := int(src.Height)
+
+	for sy := 0; sy < srcHeight; sy++ {
+		dy := y + sy
+		if dy < 0 || dy >= height {
+			continue
+		}
+
+		for sx := 0; sx < srcWidth; sx++ {
+			dx := x + sx
+			if dx < 0 || dx >= width {
+				continue
+			}
+
+			key := src.Pixels[sy*srcWidth+sx]
+			if key == 0 {
+				continue
+			}
+
+			pos := dy*width + dx
+			self.Pixels[pos] = combine(self.Pixels[pos], key)
+		}
+	}
+}
+
 func (self *Bitmap) ToImage(palette *Palette, remapping RemapSet) image.Image {
 	width := int(self.Width)
 	height := int(self.Height)
@@ -23,19 +104,7 @@ func (self *Bitmap) ToImage(palette *Palette, remapping RemapSet) image.Image {
 
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
-			key := self.Pixels[y*width+x]
-			rgb := color.RGBA{}
-			exists := true
-
-			if key >= 0xCA && key <= 0xD5 {
-				rgb = remapping.Second.Palette[key-0xCA]
-			} else if key >= 0xF3 && key <= 0xFE {
-				rgb = remapping.First.Palette[key-0xF3]
-			} else {
-				rgb, exists = palette.Color(key)
-			}
-
-			if exists {
+			if rgb, exists := resolveColor(self.Pixels[y*width+x], palette, remapping); exists {
 				img.Set(x, y, rgb)
 			}
 		}
@@ -43,3 +112,61 @@ func (self *Bitmap) ToImage(palette *Palette, remapping RemapSet) image.Image {
 
 	return img
 }
+
+// Wraps the bitmap in an image.PalettedImage. Unlike ToImage, no pixels are
+// copied; colors are looked up when they are accessed.
+func (self *Bitmap) Paletted(palette *Palette, remapping RemapSet) *PalettedBitmap {
+	colors := make(color.Palette, 256)
+
+	for i := range colors {
+		if rgb, exists := resolveColor(byte(i), palette, remapping); exists {
+			colors[i] = rgb
+		} else {
+			colors[i] = color.RGBA{}
+		}
+	}
+
+	// index 0 is always transparent, even if the palette covers it
+	colors[0] = color.RGBA{}
+
+	return &PalettedBitmap{self, colors}
+}
+
+// PalettedBitmap implements image.PalettedImage on top of a Bitmap. Changes to
+// the bitmap's pixels are visible immediately.
+type PalettedBitmap struct {
+	Bitmap *Bitmap
+	colors color.Palette
+}
+
+func (p *PalettedBitmap) ColorModel() color.Model {
+	return p.colors
+}
+
+func (p *PalettedBitmap) Bounds() image.Rectangle {
+	return image.Rect(0, 0, int(p.Bitmap.Width), int(p.Bitmap.Height))
+}
+
+func (p *PalettedBitmap) At(x int, y int) color.Color {
+	return p.colors[p.ColorIndexAt(x, y)]
+}
+
+// Returns the palette index at (x,y); positions outside of the bitmap are
+// transparent (index 0).
+func (p *PalettedBitmap) ColorIndexAt(x int, y int) uint8 {
+	return p.Bitmap.pixel(x, y)
+}
+
+// resolveColor returns the color of a palette index, taking the remappable
+// ranges into account.
+func resolveColor(key byte, palette *Palette, remapping RemapSet) (color.RGBA, bool) {
+	if key >= 0xCA && key <= 0xD5 {
+		return remapping.Second.Palette[key-0xCA], true
+	}
+
+	if key >= 0xF3 && key <= 0xFE {
+		return remapping.First.Palette[key-0xF3], true
+	}
+
+	return palette.Color(key)
+}
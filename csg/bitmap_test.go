@@ -0,0 +1,76 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPalettedBitmap(t *testing.T) {
+	palette := Palette{0: {9, 9, 9, 255}, 1: {1, 2, 3, 255}, 2: {4, 5, 6, 255}}
+	remapSet, _ := NewRemapSet(0, 0, 0)
+	bitmap := &Bitmap{2, 2, []byte{0, 1, 2, 0xF4}}
+
+	var img image.PalettedImage = bitmap.Paletted(&palette, remapSet)
+
+	if index := img.ColorIndexAt(1, 0); index != 1 {
+		t.Errorf("Expected index 1 at (1,0), got %d.", index)
+	}
+
+	if c := img.At(0, 1); c != palette[2] {
+		t.Errorf("Expected color %v at (0,1), got %v.", palette[2], c)
+	}
+
+	if c := img.At(1, 1); c != RemapPalettes[0].Palette[1] {
+		t.Errorf("Expected remapped color %v at (1,1), got %v.", RemapPalettes[0].Palette[1], c)
+	}
+
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Error("Expected (0,0) to be transparent, even though the palette covers index 0.")
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	//	. . . .
+	//	. 1 2 .
+	//	. 3 . .
+	bitmap := &Bitmap{4, 3, []byte{0, 0, 0, 0, 0, 1, 2, 0, 0, 3, 0, 0}}
+	element := IndexStruct{Width: 4, Height: 3, XOffset: -2, YOffset: -5}
+
+	trimmed, trimmedElement := bitmap.Trim(element)
+	assertBitmap(t, "trimmed", trimmed, 2, 2, []byte{1, 2, 3, 0})
+	assertOffsets(t, "trimmed", trimmedElement, -1, -4)
+
+	scaled, scaledElement, err := trimmed.Scale(trimmedElement, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertBitmap(t, "scaled", scaled, 4, 4, []byte{1, 1, 2, 2, 1, 1, 2, 2, 3, 3, 0, 0, 3, 3, 0, 0})
+	assertOffsets(t, "scaled", scaledElement, -2, -8)
+
+	flipped, flippedElement := trimmed.FlipHorizontal(trimmedElement)
+	assertBitmap(t, "flipped", flipped, 2, 2, []byte{2, 1, 0, 3})
+	assertOffsets(t, "flipped", flippedElement, -1, -4)
+
+	padded, paddedElement, err := trimmed.Pad(trimmedElement, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertBitmap(t, "padded", padded, 3, 3, []byte{0, 0, 0, 0, 1, 2, 0, 3, 0})
+	assertOffsets(t, "padded", paddedElement, -2, -5)
+}
+
+func assertBitmap(t *testing.T, name string, bitmap *Bitmap, width uint16, height uint16, pixels []byte) {
+	if bitmap.Width != width || bitmap.Height != height || string(bitmap.Pixels) != string(pixels) {
+		t.Errorf("The %s bitmap does not meet the expectation.\nExpected: %dx%d % X\nActual..: %dx%d % X\n", name, width, height, pixels, bitmap.Width, bitmap.Height, bitmap.Pixels)
+	}
+}
+
+func assertOffsets(t *testing.T, name string, element IndexStruct, x int16, y int16) {
+	if element.XOffset != x || element.YOffset != y {
+		t.Errorf("The %s offsets do not meet the expectation.\nExpected: %d/%d\nActual..: %d/%d\n", name, x, y, element.XOffset, element.YOffset)
+	}
+}
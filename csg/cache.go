@@ -0,0 +1,79 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"container/list"
+	"sync"
+)
+
+// bitmapCache is a LRU cache for decoded bitmaps. Bitmaps are copied when they go
+// in and out, so callers are free to modify them.
+type bitmapCache struct {
+	lock     sync.Mutex
+	capacity int
+	order    *list.List // most recently used elements are at the front
+	entries  map[IndexStruct]*list.Element
+}
+
+type cacheEntry struct {
+	key    IndexStruct
+	bitmap *Bitmap
+}
+
+func newBitmapCache(capacity int) *bitmapCache {
+	return &bitmapCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[IndexStruct]*list.Element),
+	}
+}
+
+func (c *bitmapCache) get(key IndexStruct) (*Bitmap, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return element.Value.(*cacheEntry).bitmap.clone(), true
+}
+
+func (c *bitmapCache) add(key IndexStruct, bitmap *Bitmap) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+
+	if element, exists := c.entries[key]; exists {
+		element.Value.(*cacheEntry).bitmap = bitmap.clone()
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key, bitmap.clone()})
+	c.evict()
+}
+
+func (c *bitmapCache) resize(capacity int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.capacity = capacity
+	c.evict()
+}
+
+func (c *bitmapCache) evict() {
+	for c.order.Len() > c.capacity && c.order.Len() > 0 {
+		oldest := c.order.Back()
+
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// SpriteChangeType is a bitmask describing how a sprite differs between two data sets.
+type SpriteChangeType int
+
+const (
+	SpriteAdded SpriteChangeType = 1 << iota
+	SpriteRemoved
+	SpriteTypeChanged
+	SpriteResized
+	SpriteReanchored
+	SpritePixelsChanged
+	SpriteDamaged
+	SpriteFlagsChanged // flags other than the type, or the padding bytes
+)
+
+var spriteChangeNames = []string{"added", "removed", "type changed", "resized", "re-anchored", "pixels changed", "damaged", "flags changed"}
+
+func (t SpriteChangeType) String() string {
+	names := make([]string, 0)
+
+	for i, name := range spriteChangeNames {
+		if t&(1<<uint(i)) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "unchanged"
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// SpriteChange describes a single changed element. Old or New are nil if the
+// sprite was added or removed. The decoded bitmaps are only set for bitmaps that
+// exist in both data sets.
+type SpriteChange struct {
+	Number    int
+	Changes   SpriteChangeType
+	Old       *IndexStruct
+	New       *IndexStruct
+	OldBitmap *Bitmap
+	NewBitmap *Bitmap
+	Err       error // set if either sprite could not be decoded
+}
+
+// Compares two index/graphics pairs sprite by sprite and returns all elements
+// that differ, ordered by their number.
+func Diff(oldIndex Index, oldGraphics *Graphics, newIndex Index, newGraphics *Graphics) []SpriteChange {
+	changes := make([]SpriteChange, 0)
+	count := len(oldIndex.Elements)
+
+	if len(newIndex.Elements) > count {
+		count = len(newIndex.Elements)
+	}
+
+	for number := 0; number < count; number++ {
+		if number >= len(newIndex.Elements) {
+			changes = append(changes, SpriteChange{Number: number, Changes: SpriteRemoved, Old: &oldIndex.Elements[number]})
+			continue
+		}
+
+		if number >= len(oldIndex.Elements) {
+			changes = append(changes, SpriteChange{Number: number, Changes: SpriteAdded, New: &newIndex.Elements[number]})
+			continue
+		}
+
+		change := diffElement(oldIndex, oldGraphics, newIndex, newGraphics, number)
+		if change.Changes != 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+func diffElement(oldIndex Index, oldGraphics *Graphics, newIndex Index, newGraphics *Graphics, number int) SpriteChange {
+	oldElement := &oldIndex.Elements[number]
+	newElement := &newIndex.Elements[number]
+	change := SpriteChange{Number: number, Old: oldElement, New: newElement}
+
+	if oldElement.Type != newElement.Type {
+		change.Changes |= SpriteTypeChanged
+	}
+
+	if oldElement.Width != newElement.Width || oldElement.Height != newElement.Height {
+		change.Changes |= SpriteResized
+	}
+
+	if oldElement.XOffset != newElement.XOffset || oldElement.YOffset != newElement.YOffset {
+		change.Changes |= SpriteReanchored
+	}
+
+	if oldElement.Flags&^0x0F != newElement.Flags&^0x0F || oldElement.Padding != newElement.Padding {
+		change.Changes |= SpriteFlagsChanged
+	}
+
+	switch {
+	case isBitmap(oldElement) && isBitmap(newElement):
+		oldBitmap, oldErr := oldGraphics.ExtractBitmapAt(oldIndex, number)
+		newBitmap, newErr := newGraphics.ExtractBitmapAt(newIndex, number)
+
+		if oldErr != nil || newErr != nil {
+			change.Changes |= SpriteDamaged
+			change.Err = oldErr
+			if change.Err == nil {
+				change.Err = newErr
+			}
+
+			break
+		}
+
+		if change.Changes&SpriteResized > 0 || !bytes.Equal(oldBitmap.Pixels, newBitmap.Pixels) {
+			change.Changes |= SpritePixelsChanged
+		}
+
+		if change.Changes != 0 {
+			change.OldBitmap = oldBitmap
+			change.NewBitmap = newBitmap
+		}
+
+	case oldElement.Type == PaletteType && newElement.Type == PaletteType:
+		oldPalette, oldErr := oldGraphics.ExtractPalette(*oldElement)
+		newPalette, newErr := newGraphics.ExtractPalette(*newElement)
+
+		if oldErr != nil || newErr != nil {
+			change.Changes |= SpriteDamaged
+			change.Err = oldErr
+			if change.Err == nil {
+				change.Err = newErr
+			}
+
+			break
+		}
+
+		if !palettesEqual(oldPalette, newPalette) {
+			change.Changes |= SpritePixelsChanged
+		}
+	}
+
+	return change
+}
+
+// Renders the old and new bitmap next to each other, followed by a mask that
+// highlights every changed pixel. Returns nil if the change has no bitmaps.
+func (c *SpriteChange) SideBySide(palette *Palette, remapping RemapSet) image.Image {
+	if c.OldBitmap == nil || c.NewBitmap == nil {
+		return nil
+	}
+
+	gap := 4
+	oldWidth := int(c.OldBitmap.Width)
+	newWidth := int(c.NewBitmap.Width)
+	maskWidth := oldWidth
+	maskHeight := int(c.OldBitmap.Height)
+
+	if newWidth > maskWidth {
+		maskWidth = newWidth
+	}
+
+	if int(c.NewBitmap.Height) > maskHeight {
+		maskHeight = int(c.NewBitmap.Height)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, oldWidth+newWidth+maskWidth+2*gap, maskHeight))
+
+	draw.Draw(img, image.Rect(0, 0, oldWidth, maskHeight), c.OldBitmap.ToImage(palette, remapping), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(oldWidth+gap, 0, oldWidth+gap+newWidth, maskHeight), c.NewBitmap.ToImage(palette, remapping), image.Point{}, draw.Src)
+
+	changed := color.RGBA{255, 0, 255, 255}
+	unchanged := color.RGBA{64, 64, 64, 255}
+	left := oldWidth + newWidth + 2*gap
+
+	for y := 0; y < maskHeight; y++ {
+		for x := 0; x < maskWidth; x++ {
+			if c.OldBitmap.pixel(x, y) != c.NewBitmap.pixel(x, y) {
+				img.Set(left+x, y, changed)
+			} else {
+				img.Set(left+x, y, unchanged)
+			}
+		}
+	}
+
+	return img
+}
+
+func isBitmap(element *IndexStruct) bool {
+	return element.Type == DirectBitmapType || element.Type == CompactedBitmapType
+}
+
+func palettesEqual(a *Palette, b *Palette) bool {
+	if len(*a) != len(*b) {
+		return false
+	}
+
+	for key, c := range *a {
+		if other, exists := (*b)[key]; !exists || other != c {
+			return false
+		}
+	}
+
+	return true
+}
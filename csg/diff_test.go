@@ -0,0 +1,60 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(compactedTestData), int64(len(compactedTestData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// same data, but the last pixel of row 1 has another color
+	changedData := append([]byte{}, compactedTestData...)
+	changedData[len(changedData)-1] = 0x05
+
+	changedGraphics, err := NewGraphicsFromReaderAt(bytes.NewReader(changedData), int64(len(changedData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved := compactedTestIndex
+	moved.XOffset = 3
+
+	flagged := compactedTestIndex
+	flagged.Flags = 0x20
+	flagged.Padding = [3]byte{1, 0, 0}
+
+	oldIndex := Index{Elements: []IndexStruct{compactedTestIndex, compactedTestIndex, compactedTestIndex, compactedTestIndex}}
+	newIndex := Index{Elements: []IndexStruct{compactedTestIndex, moved, flagged}}
+
+	if changes := Diff(oldIndex, graphics, oldIndex, graphics); len(changes) != 0 {
+		t.Errorf("Expected no changes between identical pairs, got %v.", changes)
+	}
+
+	expected := map[int]SpriteChangeType{
+		1: SpriteReanchored,
+		2: SpriteFlagsChanged,
+		3: SpriteRemoved,
+	}
+
+	changes := Diff(oldIndex, graphics, newIndex, graphics)
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %v.", len(expected), changes)
+	}
+
+	for _, change := range changes {
+		if change.Changes != expected[change.Number] {
+			t.Errorf("Expected sprite %d to be %s, got %s.", change.Number, expected[change.Number], change.Changes)
+		}
+	}
+
+	changes = Diff(oldIndex, graphics, oldIndex, changedGraphics)
+	if len(changes) != 4 || changes[0].Changes != SpritePixelsChanged || changes[0].NewBitmap == nil {
+		t.Errorf("Expected every sprite's pixels to have changed, got %v.", changes)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+//go:generate stringer -type=Edition -output=edition_strings.go
+
+package csg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Edition identifies the game release a CSG file pair belongs to. The expansion
+// packs append their sprites to the ones of the original game, so sprite numbers
+// are stable across editions.
+//
+// Only Loopy Landscapes (which also ships with the Deluxe edition) can be
+// detected, as the files of the original game and of Added Attractions have not
+// been measured yet. Their indexes are reported as UnknownEdition.
+type Edition int
+
+const (
+	UnknownEdition Edition = iota
+	LoopyLandscapesEdition
+)
+
+type EditionInfo struct {
+	Edition  Edition
+	Elements int   // number of elements in CSG1i.DAT
+	DataSize int64 // size of CSG1.DAT in bytes
+
+	// Bitmaps that first appear in this edition. An index containing all of them
+	// belongs to this edition or a newer one, even if its element count does not
+	// match (e.g. because of modded files).
+	Signatures []int
+}
+
+// Editions lists what is known about each edition's files, oldest first. The
+// numbers for Loopy Landscapes are the ones OpenRCT2 checks for; its last sprite
+// is used as its signature.
+var Editions = []EditionInfo{
+	{LoopyLandscapesEdition, 69917, 41402869, []int{69916}},
+}
+
+// Returns what is known about the edition, or false if it is not listed in
+// Editions.
+func (e Edition) Info() (EditionInfo, bool) {
+	for _, info := range Editions {
+		if info.Edition == e {
+			return info, true
+		}
+	}
+
+	return EditionInfo{}, false
+}
+
+// Returns true if the sprite number exists in this edition. For unknown
+// editions, this is always true.
+func (e Edition) Contains(number int) bool {
+	info, known := e.Info()
+	if !known {
+		return number >= 0
+	}
+
+	return number >= 0 && number < info.Elements
+}
+
+// Determines the edition of the index. An exact match of the element count is
+// preferred; otherwise the newest edition whose signature sprites are all
+// present as bitmaps wins.
+func DetectEdition(index Index) Edition {
+	count := len(index.Elements)
+
+	for _, info := range Editions {
+		if info.Elements == count {
+			return info.Edition
+		}
+	}
+
+	for i := len(Editions) - 1; i >= 0; i-- {
+		if hasSignatures(index, Editions[i].Signatures) {
+			return Editions[i].Edition
+		}
+	}
+
+	return UnknownEdition
+}
+
+func hasSignatures(index Index, signatures []int) bool {
+	if len(signatures) == 0 {
+		return false
+	}
+
+	for _, number := range signatures {
+		if number < 0 || number >= len(index.Elements) {
+			return false
+		}
+
+		element := index.Elements[number]
+
+		if element.Type != DirectBitmapType && element.Type != CompactedBitmapType {
+			return false
+		}
+
+		if element.Width == 0 || element.Height == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Detects the edition of the index and makes sure the graphics belong to the
+// same edition. Besides comparing the data size, the edition's signature sprites
+// and a few others (the first, the last and the one stored last in the data file)
+// are decoded, which fails if the data was written for a different index. For
+// an UnknownEdition index, only the decoding is checked.
+//
+// Tools should refuse to work with pairs that produce an error, as they would
+// render garbage.
+func CheckEdition(index Index, graphics *Graphics) (Edition, error) {
+	edition := DetectEdition(index)
+
+	if info, known := edition.Info(); known && info.DataSize > 0 && info.DataSize != graphics.Size() {
+		return edition, fmt.Errorf("The index belongs to %s, but the data file has %d bytes instead of %d.", edition, graphics.Size(), info.DataSize)
+	}
+
+	bitmaps := make([]int, 0)
+
+	for number, element := range index.Elements {
+		if element.Type == DirectBitmapType || element.Type == CompactedBitmapType {
+			bitmaps = append(bitmaps, number)
+		}
+	}
+
+	if len(bitmaps) == 0 {
+		return edition, errors.New("The index does not contain any bitmaps.")
+	}
+
+	// the element with the highest start address must fit into the data file
+	byAddress := make([]int, len(bitmaps))
+	copy(byAddress, bitmaps)
+
+	sort.Slice(byAddress, func(i, j int) bool {
+		return index.Elements[byAddress[i]].StartAddress < index.Elements[byAddress[j]].StartAddress
+	})
+
+	signatures := []int{bitmaps[0], bitmaps[len(bitmaps)-1], byAddress[len(byAddress)-1]}
+
+	if info, known := edition.Info(); known {
+		signatures = append(signatures, info.Signatures...)
+	}
+
+	for _, number := range signatures {
+		if _, err := graphics.ExtractBitmapAt(index, number); err != nil {
+			return edition, fmt.Errorf("The data file does not match the index: %s", err)
+		}
+	}
+
+	return edition, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testEditionIndex(count int) Index {
+	index := Index{Elements: make([]IndexStruct, count)}
+
+	for i := range index.Elements {
+		index.Elements[i] = compactedTestIndex
+	}
+
+	return index
+}
+
+func TestDetectEdition(t *testing.T) {
+	if edition := DetectEdition(Index{Elements: make([]IndexStruct, 69917)}); edition != LoopyLandscapesEdition {
+		t.Errorf("Expected Loopy Landscapes for 69917 elements, got %s.", edition)
+	}
+
+	defer func(editions []EditionInfo) { Editions = editions }(Editions)
+
+	size := int64(len(compactedTestData))
+	Editions = []EditionInfo{
+		{LoopyLandscapesEdition, 8, size, []int{7}},
+	}
+
+	testcases := []struct {
+		elements int
+		expected Edition
+	}{
+		{8, LoopyLandscapesEdition},
+		{9, LoopyLandscapesEdition}, // by signature
+		{5, UnknownEdition},
+	}
+
+	for _, testcase := range testcases {
+		if edition := DetectEdition(testEditionIndex(testcase.elements)); edition != testcase.expected {
+			t.Errorf("Expected %s for %d elements, got %s.", testcase.expected, testcase.elements, edition)
+		}
+	}
+
+	// a signature sprite that is not a bitmap does not count
+	index := testEditionIndex(9)
+	index.Elements[7].Type = PaletteType
+
+	if edition := DetectEdition(index); edition != UnknownEdition {
+		t.Errorf("Expected no edition without a valid signature, got %s.", edition)
+	}
+
+	if !LoopyLandscapesEdition.Contains(7) || LoopyLandscapesEdition.Contains(8) || !UnknownEdition.Contains(100) {
+		t.Error("Loopy Landscapes should contain exactly the sprites 0 to 7.")
+	}
+
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(compactedTestData), size)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if edition, err := CheckEdition(testEditionIndex(8), graphics); err != nil || edition != LoopyLandscapesEdition {
+		t.Errorf("Expected a valid Loopy Landscapes pair, got %s (%v).", edition, err)
+	}
+
+	// an unknown index is still checked against the data
+	broken := testEditionIndex(5)
+	broken.Elements[4].StartAddress = 100
+
+	if _, err := CheckEdition(broken, graphics); err == nil {
+		t.Error("Expected an index that does not match the data to be reported.")
+	}
+
+	Editions[0].DataSize = size + 1
+
+	if _, err := CheckEdition(testEditionIndex(8), graphics); err == nil {
+		t.Error("Expected a data size mismatch to be reported.")
+	}
+}
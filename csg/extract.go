@@ -0,0 +1,89 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+type ExtractOptions struct {
+	// Number of concurrent decoders; defaults to the number of CPUs.
+	Workers int
+
+	// Size of the result channel's buffer.
+	Buffer int
+
+	// If set, only elements for which Filter returns true are extracted.
+	Filter func(number int, element IndexStruct) bool
+}
+
+// ExtractResult is the outcome of decoding a single index element. Number is the
+// element's position in the index.
+type ExtractResult struct {
+	Number  int
+	Element IndexStruct
+	Bitmap  *Bitmap
+	Err     error
+}
+
+// Decodes all bitmaps in the index concurrently and streams the results in no
+// particular order. Palettes are skipped. The returned channel is closed once
+// all bitmaps have been processed or the context has been cancelled.
+func (self *Graphics) ExtractAll(ctx context.Context, index Index, opts ExtractOptions) <-chan ExtractResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+	results := make(chan ExtractResult, opts.Buffer)
+	wg := sync.WaitGroup{}
+
+	// feed the workers
+	go func() {
+		defer close(jobs)
+
+		for number, element := range index.Elements {
+			if element.Type != DirectBitmapType && element.Type != CompactedBitmapType {
+				continue
+			}
+
+			if opts.Filter != nil && !opts.Filter(number, element) {
+				continue
+			}
+
+			select {
+			case jobs <- number:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for number := range jobs {
+				bitmap, err := self.ExtractBitmapAt(index, number)
+
+				select {
+				case results <- ExtractResult{number, index.Elements[number], bitmap, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestExtractAll(t *testing.T) {
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(compactedTestData), int64(len(compactedTestData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// element 5 is too large for the data, 7 is a palette and 3 is filtered out
+	index := Index{Elements: make([]IndexStruct, 10)}
+	for i := range index.Elements {
+		index.Elements[i] = compactedTestIndex
+	}
+
+	index.Elements[5] = IndexStruct{Width: 10, Height: 10, Type: DirectBitmapType}
+	index.Elements[7] = IndexStruct{Width: 1, Type: PaletteType}
+
+	opts := ExtractOptions{
+		Workers: 3,
+		Filter: func(number int, element IndexStruct) bool {
+			return number != 3
+		},
+	}
+
+	seen := make(map[int]bool)
+
+	for result := range graphics.ExtractAll(context.Background(), index, opts) {
+		if seen[result.Number] {
+			t.Errorf("Element %d was extracted twice.", result.Number)
+		}
+
+		seen[result.Number] = true
+
+		if result.Number == 5 {
+			extractErr, ok := result.Err.(*ExtractError)
+			if !ok || extractErr.Number != 5 {
+				t.Errorf("Expected an extract error for element 5, got %v.", result.Err)
+			}
+
+			continue
+		}
+
+		if result.Err != nil || result.Bitmap == nil || result.Element != compactedTestIndex {
+			t.Errorf("Element %d was not extracted: %v", result.Number, result.Err)
+		}
+	}
+
+	if len(seen) != 8 || seen[3] || seen[7] {
+		t.Errorf("Expected all elements except 3 and 7 to be extracted, got %v.", seen)
+	}
+}
+
+func TestExtractAllCancel(t *testing.T) {
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(compactedTestData), int64(len(compactedTestData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := Index{Elements: make([]IndexStruct, 1000)}
+	for i := range index.Elements {
+		index.Elements[i] = compactedTestIndex
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := graphics.ExtractAll(ctx, index, ExtractOptions{Workers: 2})
+	<-results
+	cancel()
+
+	// the channel must still be closed, long before every element was extracted
+	extracted := 1
+	for range results {
+		extracted++
+	}
+
+	if extracted >= len(index.Elements) {
+		t.Errorf("Expected cancelling to stop the extraction early, but all %d elements were extracted.", extracted)
+	}
+}
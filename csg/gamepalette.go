@@ -0,0 +1,100 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import "fmt"
+
+// Merges the given palette elements into a single palette. The elements are
+// layered in the given order, so later elements override the colors of earlier
+// ones.
+func MergePalettes(index Index, graphics *Graphics, numbers []int) (*Palette, error) {
+	merged := make(Palette)
+
+	for _, number := range numbers {
+		if number < 0 || number >= len(index.Elements) {
+			return nil, fmt.Errorf("Index element %d does not exist (index has %d elements).", number, len(index.Elements))
+		}
+
+		palette, err := graphics.ExtractPalette(index.Elements[number])
+		if err != nil {
+			return nil, fmt.Errorf("Could not extract palette %d: %s", number, err)
+		}
+
+		for key, c := range *palette {
+			merged[key] = c
+		}
+	}
+
+	return &merged, nil
+}
+
+// BasePaletteElement is the palette element holding most of the game's base
+// palette; it was used on its own before BuildGamePalette existed.
+const BasePaletteElement = 2024
+
+// Assembles the complete 256 color palette the game uses. The data file contains
+// the base palette split into several elements, followed by variations of it
+// (e.g. for water animations). Colors covered by BasePaletteElement are always
+// taken from it; every other color is taken from the first palette element that
+// covers it.
+//
+// The second return value lists all indices that no palette element covers.
+func BuildGamePalette(index Index, graphics *Graphics) (*Palette, []byte, error) {
+	layers := make([]int, 0)
+	covered := [256]bool{}
+	remaining := 256
+
+	candidates := make([]int, 0)
+
+	if BasePaletteElement < len(index.Elements) && index.Elements[BasePaletteElement].Type == PaletteType {
+		candidates = append(candidates, BasePaletteElement)
+	}
+
+	for number, element := range index.Elements {
+		if element.Type == PaletteType && number != BasePaletteElement {
+			candidates = append(candidates, number)
+		}
+	}
+
+	for _, number := range candidates {
+		element := index.Elements[number]
+		start := int(uint8(element.XOffset))
+		contributes := false
+
+		for i := start; i < start+int(element.Width) && i < 256; i++ {
+			if !covered[i] {
+				covered[i] = true
+				contributes = true
+				remaining--
+			}
+		}
+
+		if contributes {
+			layers = append(layers, number)
+		}
+
+		if remaining == 0 {
+			break
+		}
+	}
+
+	// merging in reverse order lets the first element covering a color win
+	for i, j := 0, len(layers)-1; i < j; i, j = i+1, j-1 {
+		layers[i], layers[j] = layers[j], layers[i]
+	}
+
+	palette, err := MergePalettes(index, graphics, layers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	missing := make([]byte, 0)
+
+	for i, isCovered := range covered {
+		if !isCovered {
+			missing = append(missing, byte(i))
+		}
+	}
+
+	return palette, missing, nil
+}
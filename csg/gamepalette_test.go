@@ -0,0 +1,102 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// paletteTestData holds three palettes: 4 colors at address 0, 4 colors at
+// address 12 and 2 colors at address 24. Colors are stored as BGR.
+var paletteTestData = []byte{
+	0, 0, 1, 0, 0, 2, 0, 0, 3, 0, 0, 4,
+	0, 1, 0, 0, 2, 0, 0, 3, 0, 0, 4, 0,
+	1, 0, 0, 2, 0, 0,
+}
+
+// returns an index with the three test palettes at elements 10 (covering 0-3),
+// BasePaletteElement (covering 2-5) and the element after it (covering 1-2)
+func paletteTestIndex() Index {
+	index := Index{Elements: make([]IndexStruct, BasePaletteElement+2)}
+
+	index.Elements[10] = IndexStruct{StartAddress: 0, Width: 4, XOffset: 0, Type: PaletteType}
+	index.Elements[BasePaletteElement] = IndexStruct{StartAddress: 12, Width: 4, XOffset: 2, Type: PaletteType}
+	index.Elements[BasePaletteElement+1] = IndexStruct{StartAddress: 24, Width: 2, XOffset: 1, Type: PaletteType}
+
+	return index
+}
+
+func TestMergePalettes(t *testing.T) {
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(paletteTestData), int64(len(paletteTestData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := paletteTestIndex()
+
+	palette, err := MergePalettes(index, graphics, []int{10, BasePaletteElement + 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Palette{
+		0: {1, 0, 0, 255},
+		1: {0, 0, 1, 255},
+		2: {0, 0, 2, 255},
+		3: {4, 0, 0, 255},
+	}
+
+	if !reflect.DeepEqual(*palette, expected) {
+		t.Errorf("Expected later palettes to override earlier ones, got %v.", *palette)
+	}
+
+	if _, err := MergePalettes(index, graphics, []int{len(index.Elements)}); err == nil {
+		t.Error("Expected a missing element to be rejected.")
+	}
+
+	if _, err := MergePalettes(index, graphics, []int{0}); err == nil {
+		t.Error("Expected a non-palette element to be rejected.")
+	}
+}
+
+func TestBuildGamePalette(t *testing.T) {
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(paletteTestData), int64(len(paletteTestData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := paletteTestIndex()
+
+	palette, missing, err := BuildGamePalette(index, graphics)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base, err := graphics.ExtractPalette(index.Elements[BasePaletteElement])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the base palette wins even though element 10 comes first
+	for key, c := range *base {
+		if (*palette)[key] != c {
+			t.Errorf("Expected color %d to be %v from the base palette, got %v.", key, c, (*palette)[key])
+		}
+	}
+
+	// the remaining colors come from the first element covering them
+	if c := (*palette)[0]; c != (color.RGBA{1, 0, 0, 255}) {
+		t.Errorf("Expected color 0 to come from element 10, got %v.", c)
+	}
+
+	if c := (*palette)[1]; c != (color.RGBA{2, 0, 0, 255}) {
+		t.Errorf("Expected color 1 to come from element 10, got %v.", c)
+	}
+
+	if len(*palette) != 6 || len(missing) != 250 || missing[0] != 6 {
+		t.Errorf("Expected colors 6-255 to be missing, got %d colors and %d missing.", len(*palette), len(missing))
+	}
+}
@@ -3,86 +3,218 @@
 package csg
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"image/color"
+	"io"
 	"io/ioutil"
 	"os"
 
 	"github.com/xrstf/rct/utils"
 )
 
+// Graphics gives access to the sprites in a CSG data file (CSG1.DAT).
+//
+// Sprite data is read on demand, so a Graphics created from an io.ReaderAt
+// never holds more than a single sprite and its cache in memory. All methods are
+// safe for concurrent use as long as the underlying io.ReaderAt is.
 type Graphics struct {
-	data []byte // avoid sharing the ByteSlice struct, so we have independent cursors per goroutine
+	source io.ReaderAt
+	size   int64
+	cache  *bitmapCache
 }
 
+// Reads the whole data file into memory.
 func NewGraphics(dataFile *os.File) (*Graphics, error) {
 	content, err := ioutil.ReadAll(dataFile)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Graphics{content}, nil
+	return NewGraphicsFromReaderAt(bytes.NewReader(content), int64(len(content)))
 }
 
+// Creates a Graphics that reads each sprite's bytes from r only when the sprite
+// is extracted. size is the total size of the data in bytes.
+func NewGraphicsFromReaderAt(r io.ReaderAt, size int64) (*Graphics, error) {
+	if size < 0 {
+		return nil, errors.New("The data size must not be negative.")
+	}
+
+	return &Graphics{r, size, newBitmapCache(0)}, nil
+}
+
+// Returns the size of the data in bytes.
+func (self *Graphics) Size() int64 {
+	return self.size
+}
+
+// Sets the maximum number of decoded bitmaps to keep in memory. The least
+// recently used bitmaps are evicted first. A size of 0 disables the cache.
+func (self *Graphics) SetCacheSize(size int) {
+	self.cache.resize(size)
+}
+
+// ExtractError describes a sprite that could not be decoded because its data is
+// damaged or does not match the index.
+type ExtractError struct {
+	Number int   // position of the element in the index, -1 if unknown
+	Row    int   // the row that could not be decoded, -1 if not row related
+	Offset int64 // absolute position in the data file
+	Reason string
+}
+
+func (e *ExtractError) Error() string {
+	msg := "Could not extract "
+
+	if e.Number >= 0 {
+		msg += fmt.Sprintf("element %d", e.Number)
+	} else {
+		msg += "element"
+	}
+
+	if e.Row >= 0 {
+		msg += fmt.Sprintf(", row %d", e.Row)
+	}
+
+	return msg + fmt.Sprintf(" at offset 0x%X: %s", e.Offset, e.Reason)
+}
+
+// Extracts the bitmap at the given position of the index. Errors caused by
+// damaged data are of type *ExtractError and carry the number.
+func (self *Graphics) ExtractBitmapAt(index Index, number int) (*Bitmap, error) {
+	if number < 0 || number >= len(index.Elements) {
+		return nil, fmt.Errorf("Index element %d does not exist (index has %d elements).", number, len(index.Elements))
+	}
+
+	bitmap, err := self.ExtractBitmap(index.Elements[number])
+	if extractErr, ok := err.(*ExtractError); ok {
+		extractErr.Number = number
+	}
+
+	return bitmap, err
+}
+
+// Extracts the bitmap the index struct is pointing to. Errors caused by damaged
+// data are of type *ExtractError.
 func (self *Graphics) ExtractBitmap(index IndexStruct) (*Bitmap, error) {
+	if bitmap, cached := self.cache.get(index); cached {
+		return bitmap, nil
+	}
+
+	var (
+		bitmap *Bitmap
+		err    error
+	)
+
 	switch index.Type {
 	case DirectBitmapType:
-		return self.extractDirectBitmap(index)
+		bitmap, err = self.extractDirectBitmap(index)
 
 	case CompactedBitmapType:
-		return self.extractCompactedImage(index)
+		bitmap, err = self.extractCompactedImage(index)
 
 	default:
 		return nil, errors.New("The given index struct is not pointing to a bitmap.")
 	}
-}
 
-func (self *Graphics) extractDirectBitmap(index IndexStruct) (*Bitmap, error) {
-	reader, err := self.reader(index)
 	if err != nil {
 		return nil, err
 	}
 
+	self.cache.add(index, bitmap)
+
+	return bitmap, nil
+}
+
+func (self *Graphics) extractDirectBitmap(index IndexStruct) (*Bitmap, error) {
 	width := index.Width
 	height := index.Height
-	imgData := reader.ConsumeBytes(uint32(width * height))
+	size := uint32(width) * uint32(height)
+
+	reader, err := self.reader(index.StartAddress, int64(size))
+	if err != nil {
+		return nil, err
+	}
+
+	if reader.Size() < size {
+		return nil, newExtractError(index, -1, 0, "expected %d pixels, but only %d bytes are left", size, reader.Size())
+	}
+
+	imgData := reader.ConsumeBytes(size)
 
 	return &Bitmap{width, height, imgData}, nil
 }
 
 func (self *Graphics) extractCompactedImage(index IndexStruct) (*Bitmap, error) {
-	reader, err := self.reader(index)
+	width := int(index.Width)
+	height := int(index.Height)
+	imgData := make([]byte, width*height)
+
+	// read the row offsets first to find out how many bytes the sprite occupies
+	offsets, err := self.reader(index.StartAddress, 2*int64(height))
 	if err != nil {
 		return nil, err
 	}
 
-	width := index.Width
-	height := index.Height
-	imgData := make([]byte, width*height)
+	if offsets.Size() < uint32(2*height) {
+		return nil, newExtractError(index, -1, 0, "row offset table for %d rows is truncated", height)
+	}
+
+	lastRow := uint32(0)
+	for y := 0; y < height; y++ {
+		if offset := uint32(offsets.ReadUint16(2 * uint32(y))); offset > lastRow {
+			lastRow = offset
+		}
+	}
+
+	// a row consists of at most one span per pixel, each with two header bytes
+	reader, err := self.reader(index.StartAddress, int64(lastRow)+3*int64(width)+2)
+	if err != nil {
+		return nil, err
+	}
+
+	available := reader.Size()
 
 	// taken from https://github.com/LinusU/node-rct-graphics/blob/6efe3864a93ed/src/api.js#L19
-	for y := uint16(0); y < height; y++ {
+	for y := 0; y < height; y++ {
 		isLast := false
-		startOffset := reader.ReadUint16(index.StartAddress + (2 * uint32(y)))
-		currentPos := index.StartAddress + uint32(startOffset)
+		currentPos := uint32(reader.ReadUint16(2 * uint32(y)))
+
+		if currentPos < uint32(2*height) {
+			return nil, newExtractError(index, y, currentPos, "row data points into the row offset table")
+		}
 
 		for !isLast {
-			size := reader.ReadUint8(currentPos)
-			offset := reader.ReadUint8(currentPos + 1)
+			if currentPos+2 > available {
+				return nil, newExtractError(index, y, currentPos, "span header is out of range")
+			}
 
-			currentPos += 2
+			size := int(reader.ReadUint8(currentPos))
+			offset := int(reader.ReadUint8(currentPos + 1))
 
 			isLast = size&0x80 > 0
 			size = size & 0x7F
 
-			start := y*width + uint16(offset)
-			copy(imgData[start:(start+uint16(size))], reader.ReadBytes(currentPos, uint32(size)))
+			if offset+size > width {
+				return nil, newExtractError(index, y, currentPos, "span of %d pixels at x=%d overruns the row (width is %d)", size, offset, width)
+			}
+
+			currentPos += 2
+
+			if currentPos+uint32(size) > available {
+				return nil, newExtractError(index, y, currentPos, "span of %d pixels is truncated", size)
+			}
+
+			start := y*width + offset
+			copy(imgData[start:(start+size)], reader.ReadBytes(currentPos, uint32(size)))
 
 			currentPos += uint32(size)
 		}
 	}
 
-	return &Bitmap{width, height, imgData}, nil
+	return &Bitmap{index.Width, index.Height, imgData}, nil
 }
 
 func (self *Graphics) ExtractPalette(index IndexStruct) (*Palette, error) {
@@ -90,11 +222,19 @@ func (self *Graphics) ExtractPalette(index IndexStruct) (*Palette, error) {
 		return nil, errors.New("The given index structure does not point to a palette.")
 	}
 
-	reader, err := self.reader(index)
+	if int(uint8(index.XOffset))+int(index.Width) > 256 {
+		return nil, newExtractError(index, -1, 0, "palette with %d colors starting at %d exceeds 256 colors", index.Width, uint8(index.XOffset))
+	}
+
+	reader, err := self.reader(index.StartAddress, 3*int64(index.Width))
 	if err != nil {
 		return nil, err
 	}
 
+	if reader.Size() < 3*uint32(index.Width) {
+		return nil, newExtractError(index, -1, 0, "expected %d colors, but only %d bytes are left", index.Width, reader.Size())
+	}
+
 	p := make(Palette)
 	idx := byte(uint8(index.XOffset))
 
@@ -110,12 +250,29 @@ func (self *Graphics) ExtractPalette(index IndexStruct) (*Palette, error) {
 	return &p, nil
 }
 
-func (self *Graphics) reader(index IndexStruct) (*utils.ByteSlice, error) {
-	data := utils.NewByteSlice(self.data)
-	err := data.Seek(index.StartAddress)
-	if err != nil {
+// reader returns a ByteSlice over (at most) length bytes, starting at start. All
+// positions in the returned slice are relative to start.
+func (self *Graphics) reader(start uint32, length int64) (*utils.ByteSlice, error) {
+	if int64(start) > self.size {
+		return nil, &ExtractError{-1, -1, int64(start), fmt.Sprintf("start address is beyond the end of the data (%d bytes)", self.size)}
+	}
+
+	if int64(start)+length > self.size {
+		length = self.size - int64(start)
+	}
+
+	data := make([]byte, length)
+
+	read, err := self.source.ReadAt(data, int64(start))
+	if err != nil && !(err == io.EOF && int64(read) == length) {
 		return nil, err
 	}
 
-	return data, nil
+	return utils.NewByteSlice(data), nil
+}
+
+// newExtractError creates an error for the element, pos is relative to the
+// element's start address.
+func newExtractError(index IndexStruct, row int, pos uint32, format string, args ...interface{}) *ExtractError {
+	return &ExtractError{-1, row, int64(index.StartAddress) + int64(pos), fmt.Sprintf(format, args...)}
 }
@@ -0,0 +1,80 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"testing"
+)
+
+// a 4x2 compacted sprite, located at address 3 after some garbage
+//
+//	row 0: . 1 2 .
+//	row 1: 3 . . 4
+var compactedTestData = []byte{
+	0xAA, 0xBB, 0xCC,
+	0x04, 0x00, 0x08, 0x00, // row offsets
+	0x82, 0x01, 0x01, 0x02, // row 0: last span, 2 pixels at x=1
+	0x01, 0x00, 0x03, // row 1: 1 pixel at x=0
+	0x81, 0x03, 0x04, // row 1: last span, 1 pixel at x=3
+}
+
+var compactedTestIndex = IndexStruct{StartAddress: 3, Width: 4, Height: 2, Type: CompactedBitmapType}
+
+func TestExtractCompactedImage(t *testing.T) {
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(compactedTestData), int64(len(compactedTestData)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graphics.SetCacheSize(1)
+
+	for i := 0; i < 2; i++ {
+		bitmap, err := graphics.ExtractBitmap(compactedTestIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []byte{0, 1, 2, 0, 3, 0, 0, 4}
+
+		if !bytes.Equal(bitmap.Pixels, expected) {
+			t.Errorf("Extracted pixels do not meet the expectation.\nExpected: % X\nActual..: % X\n", expected, bitmap.Pixels)
+		}
+
+		// modifying the result must not affect the cache
+		bitmap.Pixels[1] = 9
+	}
+}
+
+func TestExtractDamagedCompactedImage(t *testing.T) {
+	testcases := []struct {
+		data []byte
+		row  int
+	}{
+		{compactedTestData[:5], -1},                                         // truncated row offsets
+		{compactedTestData[:10], 0},                                         // truncated span
+		{append(append([]byte{}, compactedTestData[:7]...), 0x83, 0x02), 0}, // span overruns the row
+	}
+
+	index := Index{Elements: []IndexStruct{compactedTestIndex}}
+
+	for _, test := range testcases {
+		graphics, _ := NewGraphicsFromReaderAt(bytes.NewReader(test.data), int64(len(test.data)))
+
+		_, err := graphics.ExtractBitmapAt(index, 0)
+		if err == nil {
+			t.Errorf("Extracting from % X should have failed.", test.data)
+			continue
+		}
+
+		extractErr, ok := err.(*ExtractError)
+		if !ok {
+			t.Errorf("Expected an ExtractError, got %T: %s", err, err)
+			continue
+		}
+
+		if extractErr.Number != 0 || extractErr.Row != test.row {
+			t.Errorf("Expected error for element 0, row %d, got: %s", test.row, err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// SpriteHash identifies a sprite by its content: the size, the offsets and the
+// decoded pixels. How the sprite is stored (direct or compacted) and where it is
+// located in the data file do not affect the hash.
+type SpriteHash [sha256.Size]byte
+
+func (h SpriteHash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func (h SpriteHash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *SpriteHash) UnmarshalText(text []byte) error {
+	decoded, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+
+	if len(decoded) != len(h) {
+		return fmt.Errorf("A sprite hash must be %d bytes long, got %d bytes.", len(h), len(decoded))
+	}
+
+	copy(h[:], decoded)
+
+	return nil
+}
+
+// Computes the hash of a decoded bitmap. element provides the offsets.
+func HashBitmap(element IndexStruct, bitmap *Bitmap) SpriteHash {
+	hash := sha256.New()
+	header := make([]byte, 8)
+
+	binary.LittleEndian.PutUint16(header[0:], bitmap.Width)
+	binary.LittleEndian.PutUint16(header[2:], bitmap.Height)
+	binary.LittleEndian.PutUint16(header[4:], uint16(element.XOffset))
+	binary.LittleEndian.PutUint16(header[6:], uint16(element.YOffset))
+
+	hash.Write(header)
+	hash.Write(bitmap.Pixels)
+
+	result := SpriteHash{}
+	copy(result[:], hash.Sum(nil))
+
+	return result
+}
+
+type ManifestEntry struct {
+	Number int        `json:"number"`
+	Hash   SpriteHash `json:"hash"`
+}
+
+// Hashes every bitmap in the index and returns the entries ordered by number.
+// Fails on the first sprite that cannot be decoded.
+func BuildManifest(ctx context.Context, index Index, graphics *Graphics) ([]ManifestEntry, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	manifest := make([]ManifestEntry, 0, len(index.Elements))
+
+	for result := range graphics.ExtractAll(ctx, index, ExtractOptions{}) {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		manifest = append(manifest, ManifestEntry{result.Number, HashBitmap(result.Element, result.Bitmap)})
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(manifest, func(i, j int) bool {
+		return manifest[i].Number < manifest[j].Number
+	})
+
+	return manifest, nil
+}
+
+// Writes the manifest as a JSON array.
+func WriteManifest(w io.Writer, manifest []ManifestEntry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(manifest)
+}
+
+// Reads a manifest written by WriteManifest.
+func ReadManifest(r io.Reader) ([]ManifestEntry, error) {
+	manifest := make([]ManifestEntry, 0)
+	err := json.NewDecoder(r).Decode(&manifest)
+
+	return manifest, err
+}
+
+// Groups the sprites with identical content. Only groups with at least two
+// sprites are returned; each group is sorted and the groups are ordered by their
+// first sprite.
+func FindDuplicates(manifest []ManifestEntry) [][]int {
+	groups := make(map[SpriteHash][]int)
+
+	for _, entry := range manifest {
+		groups[entry.Hash] = append(groups[entry.Hash], entry.Number)
+	}
+
+	duplicates := make([][]int, 0)
+
+	for _, numbers := range groups {
+		if len(numbers) > 1 {
+			sort.Ints(numbers)
+			duplicates = append(duplicates, numbers)
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i][0] < duplicates[j][0]
+	})
+
+	return duplicates
+}
+
+// Finds sprites in manifest that also exist in reference, for example to detect
+// a mod reusing vanilla graphics. The result maps sprite numbers from manifest
+// to the numbers of identical sprites in reference.
+func MatchManifest(reference []ManifestEntry, manifest []ManifestEntry) map[int][]int {
+	known := make(map[SpriteHash][]int)
+
+	for _, entry := range reference {
+		known[entry.Hash] = append(known[entry.Hash], entry.Number)
+	}
+
+	matches := make(map[int][]int)
+
+	for _, entry := range manifest {
+		if numbers, exists := known[entry.Hash]; exists {
+			matches[entry.Number] = numbers
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	// the compacted test sprite, followed by the same pixels stored directly
+	data := append([]byte{}, compactedTestData...)
+	data = append(data, 0, 1, 2, 0, 3, 0, 0, 4)
+
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved := compactedTestIndex
+	moved.XOffset = 1
+
+	direct := IndexStruct{StartAddress: uint32(len(compactedTestData)), Width: 4, Height: 2, Type: DirectBitmapType}
+	palette := IndexStruct{Type: PaletteType}
+
+	index := Index{Elements: []IndexStruct{compactedTestIndex, moved, direct, palette}}
+
+	manifest, err := BuildManifest(context.Background(), index, graphics)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manifest) != 3 {
+		t.Fatalf("Expected 3 manifest entries (palettes are skipped), got %d.", len(manifest))
+	}
+
+	if manifest[0].Hash != manifest[2].Hash {
+		t.Error("The storage type should not affect the hash.")
+	}
+
+	if manifest[0].Hash == manifest[1].Hash {
+		t.Error("The offsets should affect the hash.")
+	}
+
+	if duplicates := FindDuplicates(manifest); !reflect.DeepEqual(duplicates, [][]int{{0, 2}}) {
+		t.Errorf("Expected sprites 0 and 2 to be duplicates, got %v.", duplicates)
+	}
+
+	buffer := bytes.Buffer{}
+
+	if err := WriteManifest(&buffer, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ReadManifest(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded, manifest) {
+		t.Errorf("Manifest did not survive a round trip: %v != %v.", decoded, manifest)
+	}
+
+	matches := MatchManifest(manifest, []ManifestEntry{{Number: 7, Hash: manifest[0].Hash}, {Number: 8}})
+	if !reflect.DeepEqual(matches, map[int][]int{7: {0, 2}}) {
+		t.Errorf("Expected sprite 7 to match sprites 0 and 2, got %v.", matches)
+	}
+
+	hash := SpriteHash{}
+	if err := hash.UnmarshalText([]byte("abcd")); err == nil {
+		t.Error("Expected a short hash to be rejected.")
+	}
+}
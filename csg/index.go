@@ -5,6 +5,9 @@
 package csg
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"io/ioutil"
 	"os"
 
@@ -19,32 +22,91 @@ const (
 	PaletteType         ElementType = 0x8
 )
 
+// ElementFlag is a single bit of an index element's flag byte. The lower four
+// bits make up the ElementType.
+type ElementFlag byte
+
+const (
+	TransparencyFlag ElementFlag = 0x01
+	CompressedFlag   ElementFlag = 0x04
+	PaletteFlag      ElementFlag = 0x08
+	ZoomSpriteFlag   ElementFlag = 0x10
+	NoZoomDrawFlag   ElementFlag = 0x20
+)
+
 type IndexStruct struct {
 	StartAddress uint32
 	Width        uint16
 	Height       uint16
 	XOffset      int16
 	YOffset      int16
-	Type         ElementType
+	Type         ElementType // the lower four bits of Flags
+	Flags        byte        // the raw flag byte
+	Padding      [3]byte
+}
+
+func (self *IndexStruct) Flag(flag ElementFlag) bool {
+	return self.Flags&byte(flag) > 0
+}
+
+// Returns true if pixels with color 0 are transparent.
+func (self *IndexStruct) HasTransparency() bool {
+	return self.Flag(TransparencyFlag)
+}
+
+// Returns true if the element is stored RLE compacted.
+func (self *IndexStruct) IsCompressed() bool {
+	return self.Flag(CompressedFlag)
+}
+
+// Returns true if the element is followed by a smaller version of itself, which
+// the game uses when zoomed out.
+func (self *IndexStruct) HasZoomSprite() bool {
+	return self.Flag(ZoomSpriteFlag)
+}
+
+// Returns true if the game does not draw the element when zoomed out.
+func (self *IndexStruct) NoZoomDraw() bool {
+	return self.Flag(NoZoomDrawFlag)
+}
+
+// Returns true if the ElementType is one of the known types.
+func (self *IndexStruct) IsKnownType() bool {
+	return self.Type == DirectBitmapType || self.Type == CompactedBitmapType || self.Type == PaletteType
+}
+
+// IndexWarning describes an index element that was decoded, but is not fully
+// understood.
+type IndexWarning struct {
+	Number  int
+	Element IndexStruct
+	Message string
+}
+
+func (w IndexWarning) String() string {
+	return fmt.Sprintf("Element %d: %s", w.Number, w.Message)
 }
 
 type Index struct {
 	Elements []IndexStruct
+	Warnings []IndexWarning
 }
 
 func (self *Index) Bitmaps() []IndexStruct {
-	return self.filter(DirectBitmapType | CompactedBitmapType)
+	return self.filter(DirectBitmapType, CompactedBitmapType)
 }
 
 func (self *Index) Palettes() []IndexStruct {
 	return self.filter(PaletteType)
 }
 
-func (self *Index) filter(t ElementType) []IndexStruct {
+// Returns all elements with an unknown ElementType. Decoding the index has
+// produced a warning for each of them.
+func (self *Index) Unknown() []IndexStruct {
 	result := make([]IndexStruct, 0)
 
 	for _, element := range self.Elements {
-		if element.Type&t > 0 {
+		if !element.IsKnownType() {
 			result = append(result, element)
 		}
 	}
@@ -52,6 +114,21 @@ func (self *Index) filter(t ElementType) []IndexStruct {
 	return result
 }
 
+func (self *Index) filter(types ...ElementType) []IndexStruct {
+	result := make([]IndexStruct, 0)
+
+	for _, element := range self.Elements {
+		for _, t := range types {
+			if element.Type == t {
+				result = append(result, element)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
 type IndexDecoder struct{}
 
 func NewIndexDecoder() *IndexDecoder {
@@ -67,24 +144,75 @@ func (d *IndexDecoder) DecodeFile(file *os.File) (Index, error) {
 	return d.Decode(content)
 }
 
+// Decodes the index. Elements of an unknown type are kept, but a warning is
+// added to the index for each of them.
 func (d *IndexDecoder) Decode(data []byte) (Index, error) {
+	if len(data)%16 != 0 {
+		return Index{}, fmt.Errorf("Index size must be a multiple of 16 bytes, got %d bytes.", len(data))
+	}
+
 	elements := len(data) / 16
-	result := make([]IndexStruct, 0, elements)
+	result := Index{make([]IndexStruct, 0, elements), make([]IndexWarning, 0)}
 	input := utils.NewByteSlice(data)
 
 	for input.At() < input.Size() {
-		result = append(result, IndexStruct{
-			input.ConsumeUint32(),
-			input.ConsumeUint16(),
-			input.ConsumeUint16(),
-			input.ConsumeInt16(),
-			input.ConsumeInt16(),
-			ElementType(input.ConsumeByte() & 0x0F),
-		})
-
-		// padding
-		input.Skip(3)
+		element := IndexStruct{
+			StartAddress: input.ConsumeUint32(),
+			Width:        input.ConsumeUint16(),
+			Height:       input.ConsumeUint16(),
+			XOffset:      input.ConsumeInt16(),
+			YOffset:      input.ConsumeInt16(),
+			Flags:        input.ConsumeByte(),
+		}
+
+		element.Type = ElementType(element.Flags & 0x0F)
+		copy(element.Padding[:], input.ConsumeBytes(3))
+
+		if !element.IsKnownType() {
+			result.Warnings = append(result.Warnings, IndexWarning{
+				len(result.Elements),
+				element,
+				fmt.Sprintf("unknown element type %d (flags 0x%02X)", element.Type, element.Flags),
+			})
+		}
+
+		result.Elements = append(result.Elements, element)
+	}
+
+	return result, nil
+}
+
+type IndexEncoder struct{}
+
+func NewIndexEncoder() *IndexEncoder {
+	return &IndexEncoder{}
+}
+
+// Encodes the index. For a decoded index, the result is identical to the
+// original data. The lower four bits of each element's flags are taken from its
+// Type.
+func (e *IndexEncoder) Encode(index Index) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 16*len(index.Elements)))
+
+	for _, element := range index.Elements {
+		flags := (element.Flags &^ 0x0F) | (byte(element.Type) & 0x0F)
+
+		fields := []interface{}{
+			element.StartAddress,
+			element.Width,
+			element.Height,
+			element.XOffset,
+			element.YOffset,
+			flags,
+			element.Padding,
+		}
+
+		for _, field := range fields {
+			if err := binary.Write(buf, binary.LittleEndian, field); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	return Index{result}, nil
+	return buf.Bytes(), nil
 }
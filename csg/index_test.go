@@ -0,0 +1,42 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIndexRoundtrip(t *testing.T) {
+	data := []byte{
+		0x10, 0x00, 0x00, 0x00, 0x04, 0x00, 0x02, 0x00, 0xFE, 0xFF, 0x03, 0x00, 0x35, 0x01, 0x02, 0x03, // compacted, zoom flag, padding
+		0x20, 0x00, 0x00, 0x00, 0x0C, 0x00, 0x01, 0x00, 0x0A, 0x00, 0x00, 0x00, 0x08, 0x00, 0x00, 0x00, // palette
+		0x30, 0x00, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, // unknown type
+	}
+
+	index, err := NewIndexDecoder().Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(index.Bitmaps()) != 1 || len(index.Palettes()) != 1 || len(index.Unknown()) != 1 {
+		t.Errorf("Expected one bitmap, one palette and one unknown element, got %d, %d and %d.", len(index.Bitmaps()), len(index.Palettes()), len(index.Unknown()))
+	}
+
+	if len(index.Warnings) != 1 || index.Warnings[0].Number != 2 {
+		t.Errorf("Expected one warning for element 2, got %v.", index.Warnings)
+	}
+
+	if first := index.Elements[0]; first.Type != CompactedBitmapType || !first.HasZoomSprite() || first.XOffset != -2 {
+		t.Errorf("First element was not decoded correctly: %+v", first)
+	}
+
+	encoded, err := NewIndexEncoder().Encode(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Errorf("Encoded index does not match the original.\nExpected: % X\nActual..: % X\n", data, encoded)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import "image/color"
+
+// A LookupTable maps every palette index to another one. The game uses these
+// tables to draw shadows, ghost previews and glass or water surfaces without
+// ever leaving the 8-bit palette.
+type LookupTable [256]byte
+
+// Colors commonly used to tint the scenery.
+var (
+	GhostTint = color.RGBA{119, 187, 239, 255}
+	WaterTint = color.RGBA{0, 87, 79, 255}
+	GlassTint = color.RGBA{175, 231, 251, 255}
+)
+
+// Creates a table that maps every index to itself.
+func NewIdentityTable() LookupTable {
+	table := LookupTable{}
+
+	for i := range table {
+		table[i] = byte(i)
+	}
+
+	return table
+}
+
+// Creates a table that darkens each color. amount ranges from 0 (unchanged)
+// to 1 (black).
+func NewShadowTable(palette *Palette, amount float64) LookupTable {
+	return newLookupTable(palette, func(c color.RGBA) color.RGBA {
+		return blend(c, color.RGBA{0, 0, 0, 255}, amount)
+	})
+}
+
+// Creates a table that blends each color with tint. opacity ranges from 0
+// (unchanged) to 1 (only the tint is visible).
+func NewTintTable(palette *Palette, tint color.RGBA, opacity float64) LookupTable {
+	return newLookupTable(palette, func(c color.RGBA) color.RGBA {
+		return blend(c, tint, opacity)
+	})
+}
+
+// Creates the table used for translucent construction previews.
+func NewGhostTable(palette *Palette) LookupTable {
+	return NewTintTable(palette, GhostTint, 0.5)
+}
+
+// Creates the table used for water surfaces.
+func NewWaterTable(palette *Palette) LookupTable {
+	return NewTintTable(palette, WaterTint, 0.4)
+}
+
+// Creates the table used for glass.
+func NewGlassTable(palette *Palette) LookupTable {
+	return NewTintTable(palette, GlassTint, 0.3)
+}
+
+// Returns the index the table maps key to.
+func (t *LookupTable) Lookup(key byte) byte {
+	return t[key]
+}
+
+// Returns a copy of the bitmap with the table applied to every pixel.
+// Transparent pixels stay transparent.
+func (t *LookupTable) Apply(bitmap *Bitmap) *Bitmap {
+	pixels := make([]byte, len(bitmap.Pixels))
+
+	for i, key := range bitmap.Pixels {
+		if key != 0 {
+			pixels[i] = t[key]
+		}
+	}
+
+	return &Bitmap{bitmap.Width, bitmap.Height, pixels}
+}
+
+func newLookupTable(palette *Palette, transform func(color.RGBA) color.RGBA) LookupTable {
+	table := NewIdentityTable()
+
+	// collect all indices we can safely map to; remappable colors would
+	// change with the remap set and are therefore no valid targets
+	candidates := make([]byte, 0, len(*palette))
+
+	for key := range *palette {
+		if key != 0 && !isRemapIndex(key) {
+			candidates = append(candidates, key)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return table
+	}
+
+	for key, c := range *palette {
+		if key == 0 {
+			continue
+		}
+
+		table[key] = nearestColor(palette, candidates, transform(c))
+	}
+
+	return table
+}
+
+func isRemapIndex(key byte) bool {
+	return (key >= 0xCA && key <= 0xD5) || (key >= 0xF3 && key <= 0xFE)
+}
+
+func nearestColor(palette *Palette, candidates []byte, target color.RGBA) byte {
+	best := candidates[0]
+	bestDistance := -1
+
+	for _, key := range candidates {
+		c := (*palette)[key]
+		dr := int(c.R) - int(target.R)
+		dg := int(c.G) - int(target.G)
+		db := int(c.B) - int(target.B)
+		distance := dr*dr + dg*dg + db*db
+
+		// prefer the lower index if two colors are equally close, so that
+		// the result does not depend on the map iteration order
+		if bestDistance < 0 || distance < bestDistance || (distance == bestDistance && key < best) {
+			best = key
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+func blend(c color.RGBA, other color.RGBA, amount float64) color.RGBA {
+	if amount < 0 {
+		amount = 0
+	} else if amount > 1 {
+		amount = 1
+	}
+
+	mix := func(a uint8, b uint8) uint8 {
+		return uint8(float64(a)*(1-amount) + float64(b)*amount + 0.5)
+	}
+
+	return color.RGBA{mix(c.R, other.R), mix(c.G, other.G), mix(c.B, other.B), 255}
+}
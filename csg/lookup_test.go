@@ -0,0 +1,52 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestLookupTables(t *testing.T) {
+	palette := Palette{
+		1:    {200, 200, 200, 255},
+		2:    {100, 100, 100, 255},
+		3:    {10, 10, 10, 255},
+		0xCA: {90, 90, 90, 255}, // remappable, must never be a target
+	}
+
+	identity := NewIdentityTable()
+	if identity.Lookup(42) != 42 {
+		t.Errorf("Identity table maps 42 to %d.", identity.Lookup(42))
+	}
+
+	shadow := NewShadowTable(&palette, 0.5)
+	if key := shadow.Lookup(1); key != 2 {
+		t.Errorf("Expected the shadow of color 1 to be 2, got %d.", key)
+	}
+
+	if key := shadow.Lookup(0); key != 0 {
+		t.Errorf("Transparency must stay untouched, got %d.", key)
+	}
+
+	tint := NewTintTable(&palette, color.RGBA{0, 0, 0, 255}, 1)
+	for _, key := range []byte{1, 2, 0xCA} {
+		if got := tint.Lookup(key); got != 3 {
+			t.Errorf("Expected a full black tint to map %d to 3, got %d.", key, got)
+		}
+	}
+
+	bitmap := &Bitmap{2, 1, []byte{0, 1}}
+	if applied := shadow.Apply(bitmap); applied.Pixels[0] != 0 || applied.Pixels[1] != 2 {
+		t.Errorf("Applying the shadow table yielded %v.", applied.Pixels)
+	}
+
+	mask := &Bitmap{1, 1, []byte{5}}
+	if err := bitmap.Tint(mask, 1, 0, nil); err == nil {
+		t.Error("Tinting without a table should fail.")
+	}
+
+	if err := bitmap.Tint(mask, 1, 0, &shadow); err != nil || bitmap.Pixels[1] != 2 {
+		t.Errorf("Tinting yielded %v (%v).", bitmap.Pixels, err)
+	}
+}
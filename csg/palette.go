@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"sort"
 )
 
@@ -16,7 +17,13 @@ func (p *Palette) Color(key byte) (color.RGBA, bool) {
 	return color, exists
 }
 
+// Renders the palette as a chart, labeling each color with the built-in font.
 func (p *Palette) ToImage() image.Image {
+	return p.ToImageWithFont(FallbackFont)
+}
+
+// Renders the palette as a chart, labeling each color using the given font.
+func (p *Palette) ToImageWithFont(font TextRenderer) image.Image {
 	squareSize := 46 // size of one color on the palette
 	maxPerRow := 12  // max number of colors in one row
 	colors := len(*p)
@@ -70,18 +77,44 @@ func (p *Palette) ToImage() image.Image {
 			255,
 		}
 
-		drawText(xOffset+3, yOffset+20, text, img, c)
+		font.Draw(img, xOffset+3, yOffset+20, text, c)
 	}
 
 	return img
 }
 
-func drawText(x int, y int, text string, img *image.NRGBA, color color.RGBA) {
-	charWidth := 8
-	charHeight := 7
+// FallbackFont is a tiny hand-drawn font that only knows hexadecimal numbers and the
+// minus sign; any other character except the space is drawn as a hollow box. It
+// is used whenever the game's sprite fonts are not available.
+var FallbackFont TextRenderer = bitmapFont{}
+
+type bitmapFont struct{}
 
+const (
+	charWidth  = 8
+	charHeight = 7
+)
+
+func (f bitmapFont) Measure(text string) image.Point {
+	if len(text) == 0 {
+		return image.Point{}
+	}
+
+	return image.Point{len(text)*(charWidth+1) - 1, charHeight}
+}
+
+func (f bitmapFont) Draw(img draw.Image, x int, y int, text string, color color.Color) {
 	for _, character := range []byte(text) {
-		pattern := bitmaps[character]
+		pattern, exists := bitmaps[character]
+		if !exists {
+			if character == ' ' {
+				x += charWidth + 1
+				continue
+			}
+
+			// make unsupported characters visible instead of skipping them
+			pattern = unknownCharacter
+		}
 
 		for i := 0; i < charHeight; i++ {
 			for j := 0; j < charWidth; j++ {
@@ -271,8 +304,28 @@ var bitmaps = map[byte][]uint8{
 		" ##  ## ",
 		"##    ##",
 	}),
+
+	'-': stringsToBitmap([]string{
+		"        ",
+		"        ",
+		"        ",
+		" ###### ",
+		"        ",
+		"        ",
+		"        ",
+	}),
 }
 
+var unknownCharacter = stringsToBitmap([]string{
+	"########",
+	"#      #",
+	"#      #",
+	"#      #",
+	"#      #",
+	"#      #",
+	"########",
+})
+
 func stringsToBitmap(strs []string) []uint8 {
 	result := make([]uint8, len(strs)*len(strs[0]))
 	idx := 0
@@ -0,0 +1,122 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// RemapCombination selects one entry of RemapPalettes for each of the three
+// remappable color ranges.
+type RemapCombination [3]int
+
+func (c RemapCombination) RemapSet() (RemapSet, error) {
+	return NewRemapSet(c[0], c[1], c[2])
+}
+
+// Returns one combination per remap palette, varying the given range (0 to 2)
+// while keeping the other two ranges as in base.
+func AllRemapCombinations(varying int, base RemapCombination) []RemapCombination {
+	result := make([]RemapCombination, len(RemapPalettes))
+
+	for i := range RemapPalettes {
+		result[i] = base
+		result[i][varying] = i
+	}
+
+	return result
+}
+
+type RemapPreviewOptions struct {
+	// Number of cells per row; defaults to 8.
+	Columns int
+
+	// Font for the labels; defaults to FallbackFont.
+	Font TextRenderer
+
+	// Color of the grid's background; defaults to white.
+	Background color.Color
+}
+
+// Renders the bitmap once per remap combination and lays the results out as a
+// grid. Each cell is labeled with the combination's numbers and shows the
+// DisplayColor of its three remap palettes.
+func (self *Bitmap) RemapPreview(palette *Palette, combinations []RemapCombination, opts RemapPreviewOptions) (image.Image, error) {
+	if opts.Columns <= 0 {
+		opts.Columns = 8
+	}
+
+	if opts.Font == nil {
+		opts.Font = FallbackFont
+	}
+
+	if opts.Background == nil {
+		opts.Background = color.White
+	}
+
+	padding := 6
+	swatchSize := 10
+	labels := make([]string, len(combinations))
+	labelSize := image.Point{}
+
+	for i, combination := range combinations {
+		labels[i] = fmt.Sprintf("%d %d %d", combination[0], combination[1], combination[2])
+
+		if size := opts.Font.Measure(labels[i]); size.X > labelSize.X {
+			labelSize.X = size.X
+			labelSize.Y = size.Y
+		}
+	}
+
+	cellWidth := int(self.Width)
+	if swatches := 3*swatchSize + 2*2; swatches > cellWidth {
+		cellWidth = swatches
+	}
+
+	if labelSize.X > cellWidth {
+		cellWidth = labelSize.X
+	}
+
+	cellWidth += 2 * padding
+	cellHeight := int(self.Height) + swatchSize + labelSize.Y + 4*padding
+
+	columns := opts.Columns
+	if len(combinations) < columns {
+		columns = len(combinations)
+	}
+
+	rows := (len(combinations) + opts.Columns - 1) / opts.Columns
+
+	img := image.NewNRGBA(image.Rect(0, 0, columns*cellWidth, rows*cellHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+
+	for i, combination := range combinations {
+		remapping, err := combination.RemapSet()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid remap combination %v: %s", combination, err)
+		}
+
+		left := (i % opts.Columns) * cellWidth
+		top := (i / opts.Columns) * cellHeight
+		x := left + padding
+		y := top + padding
+
+		sprite := self.ToImage(palette, remapping)
+		draw.Draw(img, image.Rect(x, y, x+int(self.Width), y+int(self.Height)), sprite, image.Point{}, draw.Over)
+		y += int(self.Height) + padding
+
+		for j, remap := range []RemapPalette{remapping.First, remapping.Second, remapping.Third} {
+			sx := x + j*(swatchSize+2)
+			draw.Draw(img, image.Rect(sx, y, sx+swatchSize, y+swatchSize), image.NewUniform(remap.DisplayColor), image.Point{}, draw.Src)
+		}
+
+		y += swatchSize + padding
+
+		opts.Font.Draw(img, x, y, labels[i], color.Black)
+	}
+
+	return img, nil
+}
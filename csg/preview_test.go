@@ -0,0 +1,77 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestRemapPreview(t *testing.T) {
+	combinations := AllRemapCombinations(1, RemapCombination{3, 4, 5})
+	if len(combinations) != len(RemapPalettes) {
+		t.Fatalf("Expected %d combinations, got %d.", len(RemapPalettes), len(combinations))
+	}
+
+	for i, combination := range combinations {
+		if combination != (RemapCombination{3, i, 5}) {
+			t.Errorf("Expected combination %d to vary the second range, got %v.", i, combination)
+		}
+	}
+
+	palette := Palette{1: {1, 2, 3, 255}}
+	bitmap := &Bitmap{1, 1, []byte{1}}
+
+	img, err := bitmap.RemapPreview(&palette, combinations[:3], RemapPreviewOptions{Columns: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each cell is as wide as the label "0 0 0" (44px) plus padding and as high
+	// as the sprite, the swatches and the label plus padding
+	if size := img.Bounds().Size(); size != image.Pt(2*56, 2*42) {
+		t.Fatalf("Expected a 112x84 grid, got %v.", size)
+	}
+
+	if c := img.At(6, 6); c != color.NRGBAModel.Convert(palette[1]) {
+		t.Errorf("Expected the sprite at (6,6), got %v.", c)
+	}
+
+	// the label of the first cell starts below the sprite and the swatches
+	if !hasColor(img, image.Rect(6, 29, 50, 36), color.Black) {
+		t.Error("Expected the first cell to have a label.")
+	}
+
+	if _, err := bitmap.RemapPreview(&palette, []RemapCombination{{99, 0, 0}}, RemapPreviewOptions{}); err == nil {
+		t.Error("Expected an invalid combination to be rejected.")
+	}
+}
+
+func TestFallbackFontUnknownCharacters(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+
+	FallbackFont.Draw(img, 0, 0, " z", color.Black)
+
+	if hasColor(img, image.Rect(0, 0, 9, 10), color.Black) {
+		t.Error("Expected spaces to stay blank.")
+	}
+
+	if !hasColor(img, image.Rect(9, 0, 20, 10), color.Black) {
+		t.Error("Expected an unknown character to be drawn as a box.")
+	}
+}
+
+func hasColor(img image.Image, area image.Rectangle, c color.Color) bool {
+	expected := color.NRGBAModel.Convert(c)
+
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if color.NRGBAModel.Convert(img.At(x, y)) == expected {
+				return true
+			}
+		}
+	}
+
+	return false
+}
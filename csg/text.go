@@ -0,0 +1,232 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// A TextRenderer measures and draws strings onto images.
+//
+// Strings are treated as single-byte game strings, so they can contain the
+// control codes defined below.
+type TextRenderer interface {
+	Measure(text string) image.Point
+	Draw(img draw.Image, x int, y int, text string, c color.Color)
+}
+
+type FontStyle int
+
+const (
+	MediumFont FontStyle = iota
+	SmallFont
+	BigFont
+)
+
+// Each sprite font covers the characters 32 (space) to 255.
+const fontGlyphCount = 224
+
+// FontSprites contains the index position of the first glyph (the space character)
+// for each font in CSG1.DAT. The layout follows the FONT_SPRITE_BASE_* values in
+// OpenRCT2's font.h: the small font comes first, followed by the medium font, the
+// tiny font (not supported here) and the big font.
+var FontSprites = map[FontStyle]int{
+	SmallFont:  2746,
+	MediumFont: 2746 + fontGlyphCount,
+	BigFont:    2746 + 3*fontGlyphCount,
+}
+
+var fontLineHeights = map[FontStyle]int{
+	MediumFont: 12,
+	SmallFont:  10,
+	BigFont:    18,
+}
+
+// Control codes as used by the game's string format. Codes 1 to 4 consume one
+// additional argument byte.
+const (
+	CodeMoveX      byte = 0x01
+	CodeNewline    byte = 0x05
+	CodeBigFont    byte = 0x08
+	CodeMediumFont byte = 0x09
+	CodeSmallFont  byte = 0x0A
+	CodeOutline    byte = 0x11
+	CodeOutlineOff byte = 0x12
+)
+
+// TextColors maps the game's color control codes to the color they select.
+var TextColors = map[byte]color.RGBA{
+	0x8E: {35, 51, 51, 255},    // black
+	0x8F: {131, 151, 151, 255}, // grey
+	0x90: {255, 255, 255, 255}, // white
+	0x91: {199, 0, 0, 255},     // red
+	0x92: {71, 175, 39, 255},   // green
+	0x93: {255, 219, 0, 255},   // yellow
+	0x94: {255, 139, 51, 255},  // topaz
+	0x95: {99, 187, 187, 255},  // celadon
+	0x96: {119, 187, 239, 255}, // baby blue
+	0x97: {183, 183, 223, 255}, // pale lavender
+	0x98: {203, 175, 111, 255}, // pale gold
+	0x99: {251, 183, 223, 255}, // light pink
+	0x9A: {131, 207, 207, 255}, // pearl aqua
+	0x9B: {211, 219, 219, 255}, // pale silver
+}
+
+type glyph struct {
+	bitmap  *Bitmap
+	xOffset int
+	yOffset int
+	advance int
+}
+
+// Font renders text using the sprite fonts from CSG1.DAT.
+type Font struct {
+	glyphs map[FontStyle][]glyph
+	style  FontStyle
+}
+
+// Loads all three sprite fonts. style selects the font that is used at the
+// beginning of each string; control codes can switch fonts mid-string.
+func NewFont(index Index, graphics *Graphics, style FontStyle) (*Font, error) {
+	font := &Font{make(map[FontStyle][]glyph), style}
+
+	for fontStyle, start := range FontSprites {
+		if start+fontGlyphCount > len(index.Elements) {
+			return nil, errors.New("The index does not contain the font sprites.")
+		}
+
+		glyphs := make([]glyph, fontGlyphCount)
+
+		for i := 0; i < fontGlyphCount; i++ {
+			element := index.Elements[start+i]
+
+			bitmap, err := graphics.ExtractBitmap(element)
+			if err != nil {
+				return nil, fmt.Errorf("Could not extract glyph %d of font %d: %s", i, fontStyle, err)
+			}
+
+			// taken from OpenRCT2's font_sprite_initialise_characters()
+			advance := int(element.Width) + 2*int(element.XOffset) - 1
+			if fontStyle == BigFont {
+				advance--
+			}
+
+			if advance < 0 {
+				advance = 0
+			}
+
+			glyphs[i] = glyph{bitmap, int(element.XOffset), int(element.YOffset), advance}
+		}
+
+		font.glyphs[fontStyle] = glyphs
+	}
+
+	return font, nil
+}
+
+// Returns the size of the rendered text in pixels.
+func (f *Font) Measure(text string) image.Point {
+	size := image.Point{}
+
+	f.walk(text, color.RGBA{}, func(g *glyph, x int, y int, style FontStyle, c color.RGBA, outline bool) {
+		if x+g.advance > size.X {
+			size.X = x + g.advance
+		}
+
+		if y+fontLineHeights[style] > size.Y {
+			size.Y = y + fontLineHeights[style]
+		}
+	})
+
+	return size
+}
+
+// Draws the text with its top left corner at (x,y). c is the initial color,
+// color control codes in the text can change it.
+func (f *Font) Draw(img draw.Image, x int, y int, text string, c color.Color) {
+	r, g, b, a := c.RGBA()
+	initial := color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+	shadow := color.RGBA{0, 0, 0, 255}
+
+	f.walk(text, initial, func(gl *glyph, gx int, gy int, style FontStyle, current color.RGBA, outline bool) {
+		left := x + gx + gl.xOffset
+		top := y + gy + gl.yOffset
+
+		if outline {
+			drawGlyph(img, gl.bitmap, left-1, top, shadow)
+			drawGlyph(img, gl.bitmap, left+1, top, shadow)
+			drawGlyph(img, gl.bitmap, left, top-1, shadow)
+			drawGlyph(img, gl.bitmap, left, top+1, shadow)
+		}
+
+		drawGlyph(img, gl.bitmap, left, top, current)
+	})
+}
+
+// walk interprets the text and calls fn for every printable character with the
+// position of its glyph relative to the text origin.
+func (f *Font) walk(text string, current color.RGBA, fn func(*glyph, int, int, FontStyle, color.RGBA, bool)) {
+	style := f.style
+	outline := false
+	x := 0
+	y := 0
+
+	for i := 0; i < len(text); i++ {
+		char := text[i]
+
+		switch {
+		case char == CodeMoveX && i+1 < len(text):
+			i++
+			x = int(text[i])
+
+		case char >= 0x02 && char <= 0x04:
+			i++ // skip the argument
+
+		case char == CodeNewline:
+			x = 0
+			y += fontLineHeights[style]
+
+		case char == CodeBigFont:
+			style = BigFont
+
+		case char == CodeMediumFont:
+			style = MediumFont
+
+		case char == CodeSmallFont:
+			style = SmallFont
+
+		case char == CodeOutline:
+			outline = true
+
+		case char == CodeOutlineOff:
+			outline = false
+
+		case char >= 32:
+			if rgb, isColor := TextColors[char]; isColor {
+				current = rgb
+				continue
+			}
+
+			g := &f.glyphs[style][char-32]
+			fn(g, x, y, style, current, outline)
+			x += g.advance
+		}
+	}
+}
+
+func drawGlyph(img draw.Image, bitmap *Bitmap, left int, top int, c color.RGBA) {
+	width := int(bitmap.Width)
+	height := int(bitmap.Height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if bitmap.Pixels[y*width+x] > 0 {
+				img.Set(left+x, top+y, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// newTestFont returns a font whose glyphs are all the same filled 3x3 square,
+// which advances by 2 pixels (1 pixel in the big font).
+func newTestFont(t *testing.T) *Font {
+	data := bytes.Repeat([]byte{1}, 9)
+
+	graphics, err := NewGraphicsFromReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := Index{Elements: make([]IndexStruct, FontSprites[BigFont]+fontGlyphCount)}
+	for i := range index.Elements {
+		index.Elements[i] = IndexStruct{Width: 3, Height: 3, Type: DirectBitmapType}
+	}
+
+	if _, err := NewFont(Index{Elements: index.Elements[:FontSprites[BigFont]]}, graphics, MediumFont); err == nil {
+		t.Error("Expected an index without all font sprites to be rejected.")
+	}
+
+	font, err := NewFont(index, graphics, MediumFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return font
+}
+
+func TestFontMeasure(t *testing.T) {
+	font := newTestFont(t)
+
+	tests := []struct {
+		text     string
+		expected image.Point
+	}{
+		{"", image.Pt(0, 0)},
+		{"AB", image.Pt(4, 12)},
+		{"A\x01\x0AB", image.Pt(12, 12)}, // move to x=10
+		{"AB\x05A", image.Pt(4, 24)},     // newline
+		{"\x0AAB", image.Pt(4, 10)},      // small font
+		{"\x08AB", image.Pt(2, 18)},      // big font
+		{"\x08A\x09A", image.Pt(3, 18)},  // big, then medium font
+		{"A\x91B\x9B", image.Pt(4, 12)},  // colors take no space
+		{"\x11AB\x12", image.Pt(4, 12)},  // neither does the outline
+		{"A\x02\x05B", image.Pt(4, 12)},  // argument bytes are skipped
+	}
+
+	for _, test := range tests {
+		if size := font.Measure(test.text); size != test.expected {
+			t.Errorf("Expected %q to measure %v, got %v.", test.text, test.expected, size)
+		}
+	}
+}
+
+func TestFontDraw(t *testing.T) {
+	font := newTestFont(t)
+	white := color.NRGBA{255, 255, 255, 255}
+	black := color.NRGBA{0, 0, 0, 255}
+	green := color.NRGBA{0, 255, 0, 255}
+
+	tests := []struct {
+		text     string
+		expected map[image.Point]color.NRGBA
+	}{
+		{"A", map[image.Point]color.NRGBA{{5, 5}: green, {4, 5}: white, {8, 5}: white}},
+		{"A\x91B", map[image.Point]color.NRGBA{{5, 5}: green, {7, 5}: {199, 0, 0, 255}, {9, 7}: {199, 0, 0, 255}}},
+		{"A\x05\x8EB", map[image.Point]color.NRGBA{{5, 5}: green, {5, 17}: {35, 51, 51, 255}}},
+		{"\x11A", map[image.Point]color.NRGBA{{5, 5}: green, {4, 5}: black, {5, 4}: black, {8, 7}: black}},
+		{"\x11\x12A", map[image.Point]color.NRGBA{{5, 5}: green, {4, 5}: white}},
+	}
+
+	for _, test := range tests {
+		img := image.NewNRGBA(image.Rect(0, 0, 20, 30))
+		for i := range img.Pix {
+			img.Pix[i] = 255
+		}
+
+		font.Draw(img, 5, 5, test.text, green)
+
+		for point, expected := range test.expected {
+			if c := img.NRGBAAt(point.X, point.Y); c != expected {
+				t.Errorf("Expected %q to draw %v at %v, got %v.", test.text, expected, point, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package csg
+
+import (
+	"errors"
+	"image"
+)
+
+// The transformations below all keep the palette indices untouched. As bitmaps
+// do not know about their offsets, each function takes the bitmap's index struct
+// and returns a copy with Width, Height, XOffset and YOffset adjusted, so the
+// sprite stays anchored at the same position.
+
+// Returns the smallest rectangle that contains all non-transparent pixels. The
+// rectangle is empty if the bitmap is fully transparent.
+func (self *Bitmap) BoundingBox() image.Rectangle {
+	width := int(self.Width)
+	height := int(self.Height)
+	box := image.Rectangle{}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if self.Pixels[y*width+x] != 0 {
+				box = box.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+
+	return box
+}
+
+// Crops the bitmap to its bounding box. A fully transparent bitmap results in
+// an empty bitmap.
+func (self *Bitmap) Trim(element IndexStruct) (*Bitmap, IndexStruct) {
+	box := self.BoundingBox()
+	result := NewBitmap(uint16(box.Dx()), uint16(box.Dy()))
+	result.Draw(self, -box.Min.X, -box.Min.Y, nil)
+
+	element.Width = result.Width
+	element.Height = result.Height
+	element.XOffset += int16(box.Min.X)
+	element.YOffset += int16(box.Min.Y)
+
+	return result, element
+}
+
+// Scales the bitmap up by an integer factor, using nearest-neighbor sampling.
+func (self *Bitmap) Scale(element IndexStruct, factor int) (*Bitmap, IndexStruct, error) {
+	if factor < 1 {
+		return nil, element, errors.New("The scaling factor must be at least 1.")
+	}
+
+	width := int(self.Width)
+	height := int(self.Height)
+
+	if width*factor > 0xFFFF || height*factor > 0xFFFF {
+		return nil, element, errors.New("The scaled bitmap would be too large.")
+	}
+
+	result := NewBitmap(uint16(width*factor), uint16(height*factor))
+	scaledWidth := width * factor
+
+	for y := 0; y < height*factor; y++ {
+		for x := 0; x < scaledWidth; x++ {
+			result.Pixels[y*scaledWidth+x] = self.Pixels[(y/factor)*width+x/factor]
+		}
+	}
+
+	element.Width = result.Width
+	element.Height = result.Height
+	element.XOffset *= int16(factor)
+	element.YOffset *= int16(factor)
+
+	return result, element, nil
+}
+
+// Mirrors the bitmap horizontally around its anchor, like the game does for some
+// directional sprites.
+func (self *Bitmap) FlipHorizontal(element IndexStruct) (*Bitmap, IndexStruct) {
+	width := int(self.Width)
+	height := int(self.Height)
+	result := NewBitmap(self.Width, self.Height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			result.Pixels[y*width+(width-1-x)] = self.Pixels[y*width+x]
+		}
+	}
+
+	element.XOffset = -(element.XOffset + int16(self.Width))
+
+	return result, element
+}
+
+// Adds transparent pixels around the bitmap.
+func (self *Bitmap) Pad(element IndexStruct, top int, right int, bottom int, left int) (*Bitmap, IndexStruct, error) {
+	if top < 0 || right < 0 || bottom < 0 || left < 0 {
+		return nil, element, errors.New("Padding must not be negative.")
+	}
+
+	width := int(self.Width) + left + right
+	height := int(self.Height) + top + bottom
+
+	if width > 0xFFFF || height > 0xFFFF {
+		return nil, element, errors.New("The padded bitmap would be too large.")
+	}
+
+	result := NewBitmap(uint16(width), uint16(height))
+	result.Draw(self, left, top, nil)
+
+	element.Width = result.Width
+	element.Height = result.Height
+	element.XOffset -= int16(left)
+	element.YOffset -= int16(top)
+
+	return result, element, nil
+}
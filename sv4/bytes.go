@@ -2,7 +2,10 @@
 
 package sv4
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+	"fmt"
+)
 
 func rol32(x uint32, shift uint) uint32 {
 	return ((x << shift) | (x >> (32 - shift)))
@@ -44,3 +47,41 @@ func (s *SaveState) readUint32(pos uint32) uint32 {
 func (s *SaveState) readInt32(pos uint32) int32 {
 	return s.data.ReadInt32(pos)
 }
+
+func (s *SaveState) writeByte(pos uint32, value byte) {
+	s.data.WriteUint8(pos, value)
+}
+
+func (s *SaveState) writeBytes(pos uint32, value []byte) {
+	s.data.WriteBytes(pos, value)
+}
+
+func (s *SaveState) writeUint8(pos uint32, value uint8) {
+	s.data.WriteUint8(pos, value)
+}
+
+func (s *SaveState) writeUint16(pos uint32, value uint16) {
+	s.data.WriteUint16(pos, value)
+}
+
+func (s *SaveState) writeInt16(pos uint32, value int16) {
+	s.data.WriteInt16(pos, value)
+}
+
+func (s *SaveState) writeUint32(pos uint32, value uint32) {
+	s.data.WriteUint32(pos, value)
+}
+
+func (s *SaveState) writeInt32(pos uint32, value int32) {
+	s.data.WriteInt32(pos, value)
+}
+
+// range checks for setters
+
+func checkRange(name string, value int, min int, max int) error {
+	if value < min || value > max {
+		return fmt.Errorf("%s must be between %d and %d, got %d.", name, min, max, value)
+	}
+
+	return nil
+}
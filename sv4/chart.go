@@ -0,0 +1,210 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+)
+
+var (
+	chartBackground = color.RGBA{211, 191, 143, 255}
+	chartGrid       = color.RGBA{163, 139, 99, 255}
+	chartLine       = color.RGBA{91, 43, 23, 255}
+	chartLabel      = color.RGBA{0, 0, 0, 255}
+)
+
+// ChartFont draws the axis labels. Any csg.TextRenderer can be used.
+type ChartFont interface {
+	Measure(text string) image.Point
+	Draw(img draw.Image, x int, y int, text string, c color.Color)
+}
+
+type ChartOptions struct {
+	// Size of the plotting area; defaults to 256x128 pixels.
+	Width  int
+	Height int
+
+	// Axis labels show the values divided by this; defaults to 1. Use 10 to
+	// label money values in whole currency units.
+	LabelDivisor int
+
+	// Font for the axis labels; without a font, no labels are drawn.
+	Font ChartFont
+}
+
+// Renders a history (like ParkRatingHistory()) as a line graph similar to the
+// ones in the game's park window. Values are drawn from left to right.
+func RenderChart(values []int, opts ChartOptions) image.Image {
+	if opts.Width <= 0 {
+		opts.Width = 256
+	}
+
+	if opts.Height <= 0 {
+		opts.Height = 128
+	}
+
+	if opts.LabelDivisor <= 0 {
+		opts.LabelDivisor = 1
+	}
+
+	low, high, step := chartRange(values)
+
+	// measure the labels to find the left margin
+	labels := make([]string, 0)
+	labelWidth := 0
+	labelHeight := 0
+
+	for value := low; value <= high; value += step {
+		label := strconv.Itoa(value / opts.LabelDivisor)
+		labels = append(labels, label)
+
+		if opts.Font == nil {
+			continue
+		}
+
+		if size := opts.Font.Measure(label); size.X > labelWidth {
+			labelWidth = size.X
+			labelHeight = size.Y
+		}
+	}
+
+	padding := 6
+	left := padding + labelWidth + padding
+	top := padding + labelHeight/2
+	img := image.NewRGBA(image.Rect(0, 0, left+opts.Width+padding, top+opts.Height+labelHeight/2+padding))
+
+	draw.Draw(img, img.Bounds(), &image.Uniform{chartBackground}, image.Point{}, draw.Src)
+
+	toY := func(value int) int {
+		return top + opts.Height - 1 - (value-low)*(opts.Height-1)/(high-low)
+	}
+
+	// horizontal grid lines with labels
+	for i, label := range labels {
+		y := toY(low + i*step)
+
+		for x := left; x < left+opts.Width; x++ {
+			img.Set(x, y, chartGrid)
+		}
+
+		if opts.Font == nil {
+			continue
+		}
+
+		size := opts.Font.Measure(label)
+		opts.Font.Draw(img, left-padding-size.X, y-size.Y/2, label, chartLabel)
+	}
+
+	// vertical axis
+	for y := top; y < top+opts.Height; y++ {
+		img.Set(left, y, chartGrid)
+	}
+
+	toX := func(i int) int {
+		if len(values) < 2 {
+			return left + opts.Width/2
+		}
+
+		return left + i*(opts.Width-1)/(len(values)-1)
+	}
+
+	for i, value := range values {
+		x, y := toX(i), toY(value)
+
+		if i > 0 {
+			drawLine(img, toX(i-1), toY(values[i-1]), x, y, chartLine)
+		} else {
+			drawLine(img, x, y, x, y, chartLine)
+		}
+	}
+
+	return img
+}
+
+// chartRange finds nice bounds for the vertical axis, always including 0.
+func chartRange(values []int) (low int, high int, step int) {
+	for _, value := range values {
+		if value < low {
+			low = value
+		}
+
+		if value > high {
+			high = value
+		}
+	}
+
+	if high == low {
+		high = low + 1
+	}
+
+	// steps of 1, 2 or 5 times a power of ten, so that there are at most 5 of them
+	step = 1
+	for magnitude := 1; step*5 < high-low; magnitude *= 10 {
+		for _, factor := range []int{1, 2, 5} {
+			if step = factor * magnitude; step*5 >= high-low {
+				break
+			}
+		}
+	}
+
+	low = floorTo(low, step)
+	high = -floorTo(-high, step)
+
+	return low, high, step
+}
+
+func floorTo(value int, step int) int {
+	if value < 0 {
+		return -((-value + step - 1) / step * step)
+	}
+
+	return value / step * step
+}
+
+// drawLine draws a two pixel thick line using Bresenham's algorithm.
+func drawLine(img draw.Image, x0 int, y0 int, x1 int, y1 int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+
+	if x0 > x1 {
+		sx = -1
+	}
+
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+
+	for {
+		img.Set(x0, y0, c)
+		img.Set(x0, y0+1, c)
+
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+
+		e2 := 2 * err
+
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+import (
+	"image"
+	"testing"
+)
+
+func TestChartRange(t *testing.T) {
+	tests := []struct {
+		values          []int
+		low, high, step int
+	}{
+		{nil, 0, 1, 1},
+		{[]int{3, 47}, 0, 50, 10},
+		{[]int{700, 900}, 0, 1000, 200},
+		{[]int{-120, 80}, -150, 100, 50},
+		{[]int{-7, -3}, -8, 0, 2},
+	}
+
+	for _, test := range tests {
+		low, high, step := chartRange(test.values)
+
+		if low != test.low || high != test.high || step != test.step {
+			t.Errorf("Expected range %d..%d in steps of %d for %v, got %d..%d in steps of %d.", test.low, test.high, test.step, test.values, low, high, step)
+		}
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	img := RenderChart([]int{0, 10}, ChartOptions{Width: 100, Height: 50})
+
+	// without a font there are no labels, only the padding around the plot
+	if size := img.Bounds().Size(); size != image.Pt(118, 62) {
+		t.Fatalf("Expected a 118x62 chart, got %v.", size)
+	}
+
+	for _, point := range []image.Point{{12, 55}, {111, 6}} {
+		if c := img.At(point.X, point.Y); c != chartLine {
+			t.Errorf("Expected the line to pass through %v, got %v.", point, c)
+		}
+	}
+}
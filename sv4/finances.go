@@ -2,41 +2,170 @@
 
 package sv4
 
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Cash is stored as a signed value, as parks can go into debt.
 func (s *SaveState) Cash() int {
-	return int(s.readUint32(0x198834))
+	return int(s.readInt32(0x198834))
+}
+
+func (s *SaveState) SetCash(cash int) error {
+	if err := checkRange("Cash", cash, math.MinInt32, math.MaxInt32); err != nil {
+		return err
+	}
+
+	s.writeInt32(0x198834, int32(cash))
+
+	return nil
 }
 
 func (s *SaveState) Loan() int {
 	return int(s.readUint32(0x198838))
 }
 
+func (s *SaveState) SetLoan(loan int) error {
+	if err := checkRange("Loan", loan, 0, math.MaxInt32); err != nil {
+		return err
+	}
+
+	s.writeInt32(0x198838, int32(loan))
+
+	return nil
+}
+
 func (s *SaveState) MaxLoan() int {
 	return int(s.readUint32(0x199548))
 }
 
+func (s *SaveState) SetMaxLoan(loan int) error {
+	if err := checkRange("Max loan", loan, 0, math.MaxInt32); err != nil {
+		return err
+	}
+
+	s.writeInt32(0x199548, int32(loan))
+
+	return nil
+}
+
 func (s *SaveState) ParkEntryFee() int {
 	return int(s.readUint16(0x198840))
 }
 
+func (s *SaveState) SetParkEntryFee(fee int) error {
+	if err := checkRange("Park entry fee", fee, 0, math.MaxUint16); err != nil {
+		return err
+	}
+
+	s.writeUint16(0x198840, uint16(fee))
+
+	return nil
+}
+
+func (s *SaveState) ParkValue() int {
+	return int(s.readInt32(0x199994))
+}
+
+// The game keeps no history of the company value, only its current value.
+func (s *SaveState) CompanyValue() int {
+	return int(s.readInt32(0x199BA4))
+}
+
+// The money histories consist of 128 entries each, most recent first. Entries
+// that have not been recorded yet hold this value.
+const undefinedMoney = math.MinInt32
+
+func (s *SaveState) readMoneyHistory(pos uint32) []int {
+	history := make([]int, 0, 128)
+
+	for i := int32(127); i >= 0; i-- {
+		if value := s.readInt32(pos + uint32(i)*4); value != undefinedMoney {
+			history = append(history, int(value))
+		}
+	}
+
+	return history
+}
+
+// Returns the last 128 recorded cash values, oldest first.
+func (s *SaveState) CashHistory() []int {
+	return s.readMoneyHistory(0x199584)
+}
+
+// Returns the last 128 recorded park values, oldest first.
+func (s *SaveState) ParkValueHistory() []int {
+	return s.readMoneyHistory(0x199998)
+}
+
 type FinanceReport struct {
-	RideConstruction int32
-	RideOperation    int32
-	LandPurchase     int32
-	Landscaping      int32
-	ParkTickets      int32
-	RideTickets      int32
-	ShopSales        int32
-	ShopStock        int32
-	FoodSales        int32
-	FoodStock        int32
-	StaffWages       int32
-	Marketing        int32
-	Research         int32
-	LoanInterest     int32
+	// Reports before the first year have a Year of 0 or lower. For yearly
+	// reports, Month is 0.
+	Year  int        `json:"year"`
+	Month time.Month `json:"month"`
+
+	RideConstruction int32 `json:"ride_construction"`
+	RideOperation    int32 `json:"ride_operation"`
+	LandPurchase     int32 `json:"land_purchase"`
+	Landscaping      int32 `json:"landscaping"`
+	ParkTickets      int32 `json:"park_tickets"`
+	RideTickets      int32 `json:"ride_tickets"`
+	ShopSales        int32 `json:"shop_sales"`
+	ShopStock        int32 `json:"shop_stock"`
+	FoodSales        int32 `json:"food_sales"`
+	FoodStock        int32 `json:"food_stock"`
+	StaffWages       int32 `json:"staff_wages"`
+	Marketing        int32 `json:"marketing"`
+	Research         int32 `json:"research"`
+	LoanInterest     int32 `json:"loan_interest"`
 }
 
+// Returns the sum of all income; the game stores income as positive values.
+func (r FinanceReport) Income() int {
+	return int(r.ParkTickets) + int(r.RideTickets) + int(r.ShopSales) + int(r.FoodSales)
+}
+
+// Returns the sum of all expenses; the game stores expenses as negative values.
+func (r FinanceReport) Expenditure() int {
+	return int(r.RideConstruction) + int(r.RideOperation) + int(r.LandPurchase) + int(r.Landscaping) +
+		int(r.ShopStock) + int(r.FoodStock) + int(r.StaffWages) + int(r.Marketing) + int(r.Research) +
+		int(r.LoanInterest)
+}
+
+func (r FinanceReport) Profit() int {
+	return r.Income() + r.Expenditure()
+}
+
+func (r *FinanceReport) add(other FinanceReport) {
+	r.RideConstruction += other.RideConstruction
+	r.RideOperation += other.RideOperation
+	r.LandPurchase += other.LandPurchase
+	r.Landscaping += other.Landscaping
+	r.ParkTickets += other.ParkTickets
+	r.RideTickets += other.RideTickets
+	r.ShopSales += other.ShopSales
+	r.ShopStock += other.ShopStock
+	r.FoodSales += other.FoodSales
+	r.FoodStock += other.FoodStock
+	r.StaffWages += other.StaffWages
+	r.Marketing += other.Marketing
+	r.Research += other.Research
+	r.LoanInterest += other.LoanInterest
+}
+
+// Number of monthly finance reports kept in a savestate.
+const FinanceReportCount = 16
+
+// Returns the monthly finance reports, the current month first.
 func (s *SaveState) FinanceReports() []FinanceReport {
-	reports := make([]FinanceReport, 16)
+	reports := make([]FinanceReport, FinanceReportCount)
+	current := (s.Year()-1)*8 + int(s.Month()-time.March)
 	pos := uint32(0x198CA0)
 	read := func() int32 {
 		defer (func() { pos = pos + 4 })()
@@ -46,7 +175,16 @@ func (s *SaveState) FinanceReports() []FinanceReport {
 
 	for i := 0; i < len(reports); i = i + 1 {
 		r := &reports[i]
+		month := current - i
+		years := month
+
+		// round down for months before the first year
+		if years < 0 {
+			years -= 7
+		}
 
+		r.Year = years/8 + 1
+		r.Month = time.March + time.Month(month-(r.Year-1)*8)
 		r.RideConstruction = read()
 		r.RideOperation = read()
 		r.LandPurchase = read()
@@ -65,3 +203,112 @@ func (s *SaveState) FinanceReports() []FinanceReport {
 
 	return reports
 }
+
+// Overwrites the monthly finance reports, the current month first. Year and
+// Month of the reports are ignored.
+func (s *SaveState) SetFinanceReports(reports []FinanceReport) error {
+	if len(reports) != FinanceReportCount {
+		return errors.New("Exactly 16 finance reports must be given.")
+	}
+
+	pos := uint32(0x198CA0)
+	write := func(value int32) {
+		s.writeInt32(pos, value)
+		pos = pos + 4
+	}
+
+	for _, r := range reports {
+		write(r.RideConstruction)
+		write(r.RideOperation)
+		write(r.LandPurchase)
+		write(r.Landscaping)
+		write(r.ParkTickets)
+		write(r.RideTickets)
+		write(r.ShopSales)
+		write(r.ShopStock)
+		write(r.FoodSales)
+		write(r.FoodStock)
+		write(r.StaffWages)
+		write(r.Marketing)
+		write(r.Research)
+		write(r.LoanInterest)
+	}
+
+	return nil
+}
+
+// Sums up monthly reports per year, ordered by year. Keep in mind that the
+// savestate only holds the last 16 months, so the oldest year is incomplete.
+func YearlyFinanceReports(reports []FinanceReport) []FinanceReport {
+	yearly := make([]FinanceReport, 0)
+
+	for i := len(reports) - 1; i >= 0; i-- {
+		report := reports[i]
+
+		if len(yearly) == 0 || yearly[len(yearly)-1].Year != report.Year {
+			yearly = append(yearly, FinanceReport{Year: report.Year})
+		}
+
+		yearly[len(yearly)-1].add(report)
+	}
+
+	return yearly
+}
+
+var financeReportColumns = []string{
+	"year", "month", "ride_construction", "ride_operation", "land_purchase", "landscaping",
+	"park_tickets", "ride_tickets", "shop_sales", "shop_stock", "food_sales", "food_stock",
+	"staff_wages", "marketing", "research", "loan_interest", "income", "expenditure", "profit",
+}
+
+// Writes the reports as CSV, including a header and the totals of each report.
+// Months are written as numbers, 0 for yearly reports.
+func WriteFinanceReportsCSV(w io.Writer, reports []FinanceReport) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(financeReportColumns); err != nil {
+		return err
+	}
+
+	for _, r := range reports {
+		values := []int{
+			r.Year, int(r.Month), int(r.RideConstruction), int(r.RideOperation), int(r.LandPurchase),
+			int(r.Landscaping), int(r.ParkTickets), int(r.RideTickets), int(r.ShopSales), int(r.ShopStock),
+			int(r.FoodSales), int(r.FoodStock), int(r.StaffWages), int(r.Marketing), int(r.Research),
+			int(r.LoanInterest), r.Income(), r.Expenditure(), r.Profit(),
+		}
+
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = strconv.Itoa(value)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// Writes the reports as a JSON array, including the totals of each report.
+func WriteFinanceReportsJSON(w io.Writer, reports []FinanceReport) error {
+	type reportWithTotals struct {
+		FinanceReport
+		Income      int `json:"income"`
+		Expenditure int `json:"expenditure"`
+		Profit      int `json:"profit"`
+	}
+
+	result := make([]reportWithTotals, len(reports))
+	for i, r := range reports {
+		result[i] = reportWithTotals{r, r.Income(), r.Expenditure(), r.Profit()}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(result)
+}
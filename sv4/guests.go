@@ -0,0 +1,110 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+type GuestItem uint32
+
+const (
+	Balloon GuestItem = 1 << iota
+	Toy
+	ParkMap
+	Photo
+	Umbrella
+	Drink
+	Burger
+	Chips
+	IceCream
+	Candyfloss
+	EmptyCan
+	Rubbish
+	EmptyBurgerBox
+	Pizza
+	Voucher
+	Popcorn
+	HotDog
+	Tentacle
+	Hat
+	ToffeeApple
+	TShirt
+	Doughnut
+	Coffee
+	EmptyCup
+	Chicken
+	Lemonade
+	EmptyBox
+	EmptyBottle
+)
+
+const (
+	guestThoughtsPos  = 0xB0
+	guestThoughtCount = 5
+	noThought         = 0xFF
+)
+
+type Thought struct {
+	Type      uint8
+	Item      uint8 // a ride, shop item etc., depending on the type
+	Freshness uint8
+	Timeout   uint8
+}
+
+type Guest struct {
+	Peep
+	Happiness     uint8
+	Energy        uint8
+	Hunger        uint8
+	Thirst        uint8
+	Nausea        uint8
+	Toilet        uint8
+	Cash          int
+	CashSpent     int
+	Items         GuestItem
+	CurrentRide   uint8 // the ride the guest is on or queuing for, NoRide otherwise
+	FavouriteRide uint8
+	Thoughts      []Thought // most recent first
+}
+
+func (g *Guest) HasItem(item GuestItem) bool {
+	return g.Items&item > 0
+}
+
+// Returns all guests in the park, including those currently entering or leaving.
+func (s *SaveState) Guests() []Guest {
+	guests := make([]Guest, 0, s.GuestCount())
+
+	s.eachPeep(guestPeep, func(index int, pos uint32) {
+		guest := Guest{
+			Peep:          s.readPeep(index, pos),
+			Energy:        s.readUint8(pos + 0x38),
+			Happiness:     s.readUint8(pos + 0x3A),
+			Nausea:        s.readUint8(pos + 0x3C),
+			Hunger:        s.readUint8(pos + 0x3E),
+			Thirst:        s.readUint8(pos + 0x3F),
+			Toilet:        s.readUint8(pos + 0x40),
+			Cash:          int(s.readInt32(pos + 0xA0)),
+			CashSpent:     int(s.readInt32(pos + 0xA4)),
+			Items:         GuestItem(s.readUint32(pos + 0xFC)),
+			CurrentRide:   NoRide,
+			FavouriteRide: s.readUint8(pos + 0xF9),
+			Thoughts:      make([]Thought, 0, guestThoughtCount),
+		}
+
+		switch guest.State {
+		case PeepQueuingFront, PeepOnRide, PeepLeavingRide, PeepQueuing, PeepEnteringRide:
+			guest.CurrentRide = s.readUint8(pos + 0x68)
+		}
+
+		for i := uint32(0); i < guestThoughtCount; i++ {
+			thought := s.readBytes(pos+guestThoughtsPos+i*4, 4)
+			if thought[0] == noThought {
+				break
+			}
+
+			guest.Thoughts = append(guest.Thoughts, Thought{thought[0], thought[1], thought[2], thought[3]})
+		}
+
+		guests = append(guests, guest)
+	})
+
+	return guests
+}
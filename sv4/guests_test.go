@@ -0,0 +1,76 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+import "testing"
+
+func TestGuests(t *testing.T) {
+	state := newTestSaveState(t)
+
+	// a staff member and a renamed guest queuing for ride 4
+	staffPos := uint32(spritesPos + 2*spriteSize)
+	state.writeUint8(staffPos, peepIdentifier)
+	state.writeUint8(staffPos+0x2E, staffPeep)
+
+	pos := uint32(spritesPos + 5*spriteSize)
+	state.writeUint8(pos, peepIdentifier)
+	state.writeUint8(pos+0x2E, guestPeep)
+	state.writeUint16(pos+0x22, userStringsOffset+1)
+	state.writeBytes(userStringsPos+userStringSize, []byte("Ada\x00"))
+	state.writeInt16(pos+0x0E, 320)
+	state.writeInt16(pos+0x10, -32)
+	state.writeInt16(pos+0x12, 48)
+	state.writeUint8(pos+0x2B, uint8(PeepQueuing))
+	state.writeUint32(pos+0x9C, 1234)
+	state.writeUint8(pos+0x38, 100)
+	state.writeUint8(pos+0x3A, 200)
+	state.writeUint8(pos+0x3C, 10)
+	state.writeUint8(pos+0x3E, 20)
+	state.writeUint8(pos+0x3F, 30)
+	state.writeUint8(pos+0x40, 40)
+	state.writeInt32(pos+0xA0, 450)
+	state.writeInt32(pos+0xA4, 120)
+	state.writeUint32(pos+0xFC, uint32(Balloon|Umbrella))
+	state.writeUint8(pos+0xF9, 7)
+	state.writeUint8(pos+0x68, 4)
+	state.writeBytes(pos+guestThoughtsPos, []byte{0x15, 4, 2, 0, 0x20, 0xFF, 1, 0, noThought, 0, 0, 0})
+
+	guests := state.Guests()
+	if len(guests) != 1 {
+		t.Fatalf("Expected 1 guest, got %d.", len(guests))
+	}
+
+	guest := guests[0]
+
+	expected := Peep{SpriteIndex: 5, ID: 1234, Name: "Ada", X: 320, Y: -32, Z: 48, State: PeepQueuing}
+	if guest.Peep != expected {
+		t.Errorf("Expected peep %+v, got %+v.", expected, guest.Peep)
+	}
+
+	if guest.Energy != 100 || guest.Happiness != 200 || guest.Nausea != 10 || guest.Hunger != 20 || guest.Thirst != 30 || guest.Toilet != 40 {
+		t.Errorf("Guest stats were decoded wrongly: %+v", guest)
+	}
+
+	if guest.Cash != 450 || guest.CashSpent != 120 {
+		t.Errorf("Expected 450 cash and 120 spent, got %d and %d.", guest.Cash, guest.CashSpent)
+	}
+
+	if !guest.HasItem(Umbrella) || guest.HasItem(Burger) {
+		t.Errorf("Items were decoded wrongly: %b", guest.Items)
+	}
+
+	if guest.CurrentRide != 4 || guest.FavouriteRide != 7 {
+		t.Errorf("Expected current ride 4 and favourite ride 7, got %d and %d.", guest.CurrentRide, guest.FavouriteRide)
+	}
+
+	if len(guest.Thoughts) != 2 || guest.Thoughts[1] != (Thought{0x20, 0xFF, 1, 0}) {
+		t.Errorf("Expected 2 thoughts, got %+v.", guest.Thoughts)
+	}
+
+	// walking guests are not on any ride, whatever the ride field says
+	state.writeUint8(pos+0x2B, uint8(PeepWalking))
+
+	if ride := state.Guests()[0].CurrentRide; ride != NoRide {
+		t.Errorf("Expected a walking guest to be on no ride, got %d.", ride)
+	}
+}
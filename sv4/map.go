@@ -0,0 +1,347 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+//go:generate stringer -type=TileElementType -output=map_strings.go
+
+package sv4
+
+import "fmt"
+
+// The map consists of 128x128 tiles. Each tile has a list of tile elements, all
+// lists are stored right after each other, row by row.
+const (
+	MapSize          = 128
+	tileElementsPos  = 0x000010
+	tileElementSize  = 8
+	maxTileElements  = 0xC000
+	lastForTileFlag  = 0x80
+	tileElementTypes = 0x3C
+)
+
+type TileElementType uint8
+
+const (
+	SurfaceElementType TileElementType = iota
+	PathElementType
+	TrackElementType
+	SmallSceneryElementType
+	EntranceElementType
+	FenceElementType
+	LargeSceneryElementType
+	BannerElementType
+)
+
+// A TileElement is one of the typed element structs below. All of them embed
+// TileElementBase, which also holds the raw bytes for everything that is not
+// understood yet.
+type TileElement interface {
+	Base() *TileElementBase
+}
+
+type TileElementBase struct {
+	X               int
+	Y               int
+	Type            TileElementType
+	Direction       uint8
+	Flags           byte
+	BaseHeight      uint8
+	ClearanceHeight uint8
+	Raw             [tileElementSize]byte
+}
+
+func (e *TileElementBase) Base() *TileElementBase {
+	return e
+}
+
+// Returns true if this is the last element of its tile.
+func (e *TileElementBase) IsLastForTile() bool {
+	return e.Flags&lastForTileFlag > 0
+}
+
+type SurfaceElement struct {
+	TileElementBase
+	Slope        uint8
+	SurfaceStyle uint8
+	EdgeStyle    uint8
+	WaterHeight  uint8 // 0 if there is no water
+	GrassLength  uint8
+	Ownership    uint8
+}
+
+// Paths use the Direction bits as part of their PathType.
+type PathElement struct {
+	TileElementBase
+	PathType       uint8
+	IsQueue        bool
+	IsSloped       bool
+	SlopeDirection uint8
+	Addition       uint8 // lamps, benches, bins etc., 0 for none
+	Edges          uint8
+	RideIndex      uint8 // only for queues
+}
+
+type TrackElement struct {
+	TileElementBase
+	TrackType uint8
+	Sequence  uint8
+	Color     uint8
+	RideIndex uint8
+}
+
+type SmallSceneryElement struct {
+	TileElementBase
+	ObjectID uint8
+	Age      uint8
+	Colors   [2]uint8
+}
+
+type EntranceType uint8
+
+const (
+	RideEntrance EntranceType = iota
+	RideExit
+	ParkEntrance
+)
+
+type EntranceElement struct {
+	TileElementBase
+	EntranceType EntranceType
+	Sequence     uint8
+	PathType     uint8
+	RideIndex    uint8
+}
+
+// NoFence marks an edge without a fence in FenceElement.Types.
+const NoFence = -1
+
+// FenceElement holds the fences on up to four edges of a tile. Unlike RCT2, RCT1
+// stores a fence type per edge instead of an object and only has one color.
+type FenceElement struct {
+	TileElementBase
+	Slope uint8
+	Color uint8  // RCT1 color index
+	Types [4]int // per edge, NoFence if the edge has none
+}
+
+type LargeSceneryElement struct {
+	TileElementBase
+	ObjectID uint16
+	Sequence uint8
+	Colors   [2]uint8
+}
+
+type BannerElement struct {
+	TileElementBase
+	Index       uint8
+	Position    uint8
+	BannerFlags uint8
+}
+
+// UnknownElement is returned for element types the game does not know about,
+// which usually means the savestate is damaged.
+type UnknownElement struct {
+	TileElementBase
+}
+
+// Map gives access to the tile elements of a savestate.
+type Map struct {
+	state *SaveState
+	tiles [MapSize * MapSize]uint32 // number of the first element of each tile
+}
+
+// Scans the tile element list and returns the map. This fails if the list is
+// damaged, i.e. if it ends before every tile has its elements.
+func (s *SaveState) Map() (*Map, error) {
+	m := &Map{state: s}
+	element := uint32(0)
+
+	for tile := 0; tile < MapSize*MapSize; tile++ {
+		m.tiles[tile] = element
+
+		for {
+			if element >= maxTileElements {
+				return nil, fmt.Errorf("Tile element list ends at tile %d/%d.", tile%MapSize, tile/MapSize)
+			}
+
+			flags := s.readByte(tileElementsPos + element*tileElementSize + 1)
+			element++
+
+			if flags&lastForTileFlag > 0 {
+				break
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Returns all elements on the tile at (x,y), ordered the way the game stores them.
+func (m *Map) Tile(x int, y int) ([]TileElement, error) {
+	if x < 0 || y < 0 || x >= MapSize || y >= MapSize {
+		return nil, fmt.Errorf("Tile %d/%d is outside of the map.", x, y)
+	}
+
+	elements := make([]TileElement, 0, 2)
+	number := m.tiles[y*MapSize+x]
+
+	for {
+		element := m.element(x, y, number)
+		elements = append(elements, element)
+
+		if element.Base().IsLastForTile() {
+			break
+		}
+
+		number++
+	}
+
+	return elements, nil
+}
+
+// Calls fn for every tile, row by row. Iteration stops at the first error
+// returned by fn.
+func (m *Map) Each(fn func(x int, y int, elements []TileElement) error) error {
+	for y := 0; y < MapSize; y++ {
+		for x := 0; x < MapSize; x++ {
+			elements, err := m.Tile(x, y)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(x, y, elements); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns all elements of the given type, in the order they are stored.
+func (m *Map) Elements(t TileElementType) ([]TileElement, error) {
+	result := make([]TileElement, 0)
+
+	err := m.Each(func(x int, y int, elements []TileElement) error {
+		for _, element := range elements {
+			if element.Base().Type == t {
+				result = append(result, element)
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// see https://github.com/OpenRCT2/OpenRCT2/blob/develop/src/openrct2/rct12/RCT12.h
+func (m *Map) element(x int, y int, number uint32) TileElement {
+	raw := [tileElementSize]byte{}
+	copy(raw[:], m.state.readBytes(tileElementsPos+number*tileElementSize, tileElementSize))
+
+	base := TileElementBase{
+		X:               x,
+		Y:               y,
+		Type:            TileElementType((raw[0] & tileElementTypes) >> 2),
+		Direction:       raw[0] & 0x03,
+		Flags:           raw[1],
+		BaseHeight:      raw[2],
+		ClearanceHeight: raw[3],
+		Raw:             raw,
+	}
+
+	switch base.Type {
+	case SurfaceElementType:
+		return &SurfaceElement{
+			TileElementBase: base,
+			Slope:           raw[4] & 0x1F,
+			SurfaceStyle:    (raw[5] >> 5) | ((raw[0] & 0x01) << 3),
+			EdgeStyle:       (raw[4] >> 5) | ((raw[0] & 0x80) >> 4),
+			WaterHeight:     raw[5] & 0x1F,
+			GrassLength:     raw[6],
+			Ownership:       raw[7],
+		}
+
+	case PathElementType:
+		pathType := ((raw[4] & 0xF0) >> 2) | (raw[0] & 0x03)
+
+		return &PathElement{
+			TileElementBase: base,
+			PathType:        pathType,
+			IsQueue:         pathType < 4, // the first four path types are the queue colors
+			IsSloped:        raw[4]&0x04 > 0,
+			SlopeDirection:  raw[4] & 0x03,
+			Addition:        raw[5] & 0x0F,
+			Edges:           raw[6],
+			RideIndex:       raw[7],
+		}
+
+	case TrackElementType:
+		return &TrackElement{
+			TileElementBase: base,
+			TrackType:       raw[4],
+			Sequence:        raw[5] & 0x0F,
+			Color:           raw[6],
+			RideIndex:       raw[7],
+		}
+
+	case SmallSceneryElementType:
+		return &SmallSceneryElement{
+			TileElementBase: base,
+			ObjectID:        raw[4],
+			Age:             raw[5],
+			Colors:          [2]uint8{raw[6] & 0x1F, raw[7] & 0x1F},
+		}
+
+	case EntranceElementType:
+		return &EntranceElement{
+			TileElementBase: base,
+			EntranceType:    EntranceType(raw[4]),
+			Sequence:        raw[5] & 0x0F,
+			PathType:        raw[6],
+			RideIndex:       raw[7],
+		}
+
+	case FenceElementType:
+		// see GetRCT1WallType() and GetRCT1WallColour() in OpenRCT2's RCT12.cpp
+		fence := &FenceElement{
+			TileElementBase: base,
+			Slope:           raw[4] & 0x1F,
+			Color:           (raw[0]&0xC0)>>3 | (raw[4]&0xE0)>>5,
+		}
+
+		types := uint16(raw[6]) | uint16(raw[7])<<8
+
+		for edge := uint(0); edge < 4; edge++ {
+			low := int(raw[5]>>(edge*2)) & 0x03
+			high := int(types>>(edge*4)) & 0x0F
+
+			if high == 0x0F {
+				fence.Types[edge] = NoFence
+			} else {
+				fence.Types[edge] = low | high<<2
+			}
+		}
+
+		return fence
+
+	case LargeSceneryElementType:
+		entry := uint16(raw[4]) | uint16(raw[5])<<8
+
+		return &LargeSceneryElement{
+			TileElementBase: base,
+			ObjectID:        entry & 0x3FF,
+			Sequence:        uint8(entry >> 10),
+			Colors:          [2]uint8{raw[6] & 0x1F, raw[7] & 0x1F},
+		}
+
+	case BannerElementType:
+		return &BannerElement{
+			TileElementBase: base,
+			Index:           raw[4],
+			Position:        raw[5],
+			BannerFlags:     raw[6],
+		}
+	}
+
+	return &UnknownElement{base}
+}
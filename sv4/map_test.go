@@ -0,0 +1,116 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+import "testing"
+
+func TestMap(t *testing.T) {
+	state := newTestSaveState(t)
+
+	if _, err := state.Map(); err == nil {
+		t.Error("A map without any last-for-tile flags should be rejected.")
+	}
+
+	// one surface element per tile, except for tile (1,0), which also has a path,
+	// tile (2,0), which also has a banner, and tile (3,0), which also has fences
+	pos := uint32(tileElementsPos)
+
+	for tile := 0; tile < MapSize*MapSize; tile++ {
+		if tile == 1 {
+			state.writeBytes(pos, []byte{0x00, 0x00, 2, 4, 0x03, 0x22, 0, 0})
+			state.writeBytes(pos+tileElementSize, []byte{0x04 | 0x02, lastForTileFlag, 2, 6, 0x50, 0, 0x0F, 7})
+			pos += 2 * tileElementSize
+			continue
+		}
+
+		if tile == 3 {
+			state.writeBytes(pos+tileElementSize, []byte{byte(FenceElementType)<<2 | 0x40, lastForTileFlag, 2, 6, 0x62, 0xE4, 0x2F, 0xF5})
+			pos += 2 * tileElementSize
+			continue
+		}
+
+		if tile == 2 {
+			state.writeBytes(pos+tileElementSize, []byte{byte(BannerElementType) << 2, lastForTileFlag, 2, 2, 3, 1, 0x01, 0})
+			pos += 2 * tileElementSize
+			continue
+		}
+
+		state.writeByte(pos+1, lastForTileFlag)
+		pos += tileElementSize
+	}
+
+	m, err := state.Map()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	elements, err := m.Tile(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(elements) != 2 {
+		t.Fatalf("Expected 2 elements on tile 1/0, got %d.", len(elements))
+	}
+
+	surface, ok := elements[0].(*SurfaceElement)
+	if !ok {
+		t.Fatalf("Expected a surface element, got %T.", elements[0])
+	}
+
+	if surface.Slope != 3 || surface.WaterHeight != 2 || surface.SurfaceStyle != 1 || surface.ClearanceHeight != 4 {
+		t.Errorf("Surface element was decoded wrongly: %+v", surface)
+	}
+
+	path, ok := elements[1].(*PathElement)
+	if !ok {
+		t.Fatalf("Expected a path element, got %T.", elements[1])
+	}
+
+	if path.PathType != 0x16 || path.IsQueue || path.Edges != 0x0F || path.X != 1 || path.Y != 0 {
+		t.Errorf("Path element was decoded wrongly: %+v", path)
+	}
+
+	surfaces, err := m.Elements(SurfaceElementType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(surfaces) != MapSize*MapSize {
+		t.Errorf("Expected %d surface elements, got %d.", MapSize*MapSize, len(surfaces))
+	}
+
+	banners, err := m.Elements(BannerElementType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(banners) != 1 {
+		t.Fatalf("Expected 1 banner element, got %d.", len(banners))
+	}
+
+	// the banner's own flags must not hide the element's flags
+	banner := banners[0].(*BannerElement)
+	if banner.Index != 3 || banner.Position != 1 || banner.BannerFlags != 0x01 || !banner.IsLastForTile() {
+		t.Errorf("Banner element was decoded wrongly: %+v", banner)
+	}
+
+	elements, err = m.Tile(3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fences on edges 1 and 2, in color 11 (0x40 >> 3 | 0x60 >> 5)
+	fence, ok := elements[1].(*FenceElement)
+	if !ok {
+		t.Fatalf("Expected a fence element, got %T.", elements[1])
+	}
+
+	if fence.Slope != 2 || fence.Color != 11 || fence.Types != [4]int{NoFence, 9, 22, NoFence} {
+		t.Errorf("Fence element was decoded wrongly: %+v", fence)
+	}
+
+	if _, err := m.Tile(MapSize, 0); err == nil {
+		t.Error("Tiles outside of the map should be rejected.")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+//go:generate stringer -type=MarketingCampaignType -output=marketing_strings.go
+
+package sv4
+
+import "errors"
+
+// Each campaign type has a status byte (the weeks left, plus campaignStarted in
+// its first week) and a target byte. For example, a park with a running park
+// advertising campaign that then starts free entry (2 weeks), a miniature
+// railway advert (4 weeks), free burgers (2 weeks) and free go karts (3 weeks):
+//
+//	status 00 00 00 00 06 00 | target 00 00 00 00 ff 00
+//	status 82 83 00 82 06 84 | target ff 18 00 06 ff 0e
+const (
+	marketingStatusPos = 0x19955A
+	marketingAssocPos  = 0x19956E
+	campaignStarted    = 0x80 // set by the game for a campaign that just started
+	campaignWeeks      = 0x3F // a campaign is running as long as weeks are left
+	noCampaignTarget   = 0xFF
+)
+
+type MarketingCampaignType uint8
+
+const (
+	FreeEntryCampaign MarketingCampaignType = iota
+	FreeRideCampaign
+	HalfPriceEntryCampaign
+	FreeFoodOrDrinkCampaign
+	ParkAdvertisingCampaign
+	RideAdvertisingCampaign
+)
+
+// Returns true if campaigns of this type need a ride or shop item as target.
+func (t MarketingCampaignType) HasTarget() bool {
+	return t == FreeRideCampaign || t == FreeFoodOrDrinkCampaign || t == RideAdvertisingCampaign
+}
+
+type MarketingCampaign struct {
+	Type      MarketingCampaignType
+	WeeksLeft int
+	Target    uint8 // a ride index, or a shop item for free food or drink
+}
+
+// Returns all currently running campaigns.
+func (s *SaveState) MarketingCampaigns() []MarketingCampaign {
+	campaigns := make([]MarketingCampaign, 0)
+
+	for t := FreeEntryCampaign; t <= RideAdvertisingCampaign; t++ {
+		status := s.readUint8(marketingStatusPos + uint32(t))
+		if status&campaignWeeks == 0 {
+			continue
+		}
+
+		campaigns = append(campaigns, MarketingCampaign{
+			Type:      t,
+			WeeksLeft: int(status & campaignWeeks),
+			Target:    s.readUint8(marketingAssocPos + uint32(t)),
+		})
+	}
+
+	return campaigns
+}
+
+// Starts a campaign, replacing a running campaign of the same type. Like the
+// game, the campaign is marked as just started. The campaign's costs are not
+// deducted.
+func (s *SaveState) StartMarketingCampaign(campaign MarketingCampaign) error {
+	if campaign.Type > RideAdvertisingCampaign {
+		return errors.New("Invalid marketing campaign type " + campaign.Type.String() + ".")
+	}
+
+	if err := checkRange("Campaign duration", campaign.WeeksLeft, 1, campaignWeeks); err != nil {
+		return err
+	}
+
+	target := uint8(noCampaignTarget)
+
+	if campaign.Type.HasTarget() {
+		if campaign.Target == noCampaignTarget {
+			return errors.New("A " + campaign.Type.String() + " needs a target.")
+		}
+
+		target = campaign.Target
+	}
+
+	s.writeUint8(marketingStatusPos+uint32(campaign.Type), campaignStarted|uint8(campaign.WeeksLeft))
+	s.writeUint8(marketingAssocPos+uint32(campaign.Type), target)
+
+	return nil
+}
+
+func (s *SaveState) CancelMarketingCampaign(campaignType MarketingCampaignType) {
+	if campaignType > RideAdvertisingCampaign {
+		return
+	}
+
+	s.writeUint8(marketingStatusPos+uint32(campaignType), 0)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarketingCampaigns(t *testing.T) {
+	state := newTestSaveState(t)
+
+	// a park advertising campaign with 6 weeks left, which is no longer marked
+	// as just started
+	state.writeBytes(marketingStatusPos, []byte{0x00, 0x00, 0x00, 0x00, 0x06, 0x00})
+	state.writeBytes(marketingAssocPos, []byte{0x00, 0x00, 0x00, 0x00, 0xFF, 0x00})
+
+	expected := []MarketingCampaign{{ParkAdvertisingCampaign, 6, 0xFF}}
+
+	if campaigns := state.MarketingCampaigns(); !reflect.DeepEqual(campaigns, expected) {
+		t.Errorf("Expected %+v, got %+v.", expected, campaigns)
+	}
+
+	// the same park after starting four more campaigns
+	state.writeBytes(marketingStatusPos, []byte{0x82, 0x83, 0x00, 0x82, 0x06, 0x84})
+	state.writeBytes(marketingAssocPos, []byte{0xFF, 0x18, 0x00, 0x06, 0xFF, 0x0E})
+
+	expected = []MarketingCampaign{
+		{FreeEntryCampaign, 2, 0xFF},
+		{FreeRideCampaign, 3, 0x18},
+		{FreeFoodOrDrinkCampaign, 2, 0x06},
+		{ParkAdvertisingCampaign, 6, 0xFF},
+		{RideAdvertisingCampaign, 4, 0x0E},
+	}
+
+	if campaigns := state.MarketingCampaigns(); !reflect.DeepEqual(campaigns, expected) {
+		t.Errorf("Expected %+v, got %+v.", expected, campaigns)
+	}
+
+	state.CancelMarketingCampaign(FreeRideCampaign)
+
+	if err := state.StartMarketingCampaign(MarketingCampaign{HalfPriceEntryCampaign, 5, 0x03}); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := state.readBytes(marketingStatusPos, 6); !reflect.DeepEqual(status, []byte{0x82, 0x00, 0x85, 0x82, 0x06, 0x84}) {
+		t.Errorf("Campaigns were written wrongly: % X", status)
+	}
+
+	if target := state.readUint8(marketingAssocPos + uint32(HalfPriceEntryCampaign)); target != noCampaignTarget {
+		t.Errorf("Expected a campaign without target to store 0x%02X, got 0x%02X.", noCampaignTarget, target)
+	}
+
+	if err := state.StartMarketingCampaign(MarketingCampaign{RideAdvertisingCampaign, 2, noCampaignTarget}); err == nil {
+		t.Error("Expected a ride advertising campaign without a ride to be rejected.")
+	}
+
+	if err := state.StartMarketingCampaign(MarketingCampaign{FreeEntryCampaign, 0, noCampaignTarget}); err == nil {
+		t.Error("Expected a campaign without any weeks to be rejected.")
+	}
+}
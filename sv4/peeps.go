@@ -0,0 +1,110 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+//go:generate stringer -type=PeepState -output=peeps_strings.go
+
+package sv4
+
+import "bytes"
+
+// Sprites (peeps, vehicles, litter, ...) are stored in a fixed-size table.
+const (
+	spritesPos     = 0x060014
+	spriteSize     = 256
+	maxSprites     = 5000
+	peepIdentifier = 1
+
+	userStringsPos    = 0x19B89C
+	userStringSize    = 32
+	userStringsOffset = 0x8000
+)
+
+const (
+	guestPeep = 0
+	staffPeep = 1
+)
+
+// NoRide is used for ride indexes if a peep is not on or near any ride.
+const NoRide = 0xFF
+
+type PeepState uint8
+
+const (
+	PeepFalling PeepState = iota
+	PeepOne
+	PeepQueuingFront
+	PeepOnRide
+	PeepLeavingRide
+	PeepWalking
+	PeepQueuing
+	PeepEnteringRide
+	PeepSitting
+	PeepPicked
+	PeepPatrolling
+	PeepMowing
+	PeepSweeping
+	PeepEnteringPark
+	PeepLeavingPark
+	PeepAnswering
+	PeepFixing
+	PeepBuying
+	PeepWatching
+	PeepEmptyingBin
+	PeepUsingBin
+	PeepWatering
+	PeepHeadingToInspection
+	PeepInspecting
+)
+
+// Peep contains the fields shared by guests and staff.
+type Peep struct {
+	SpriteIndex int
+	ID          uint32
+	Name        string // empty unless the player renamed the peep
+	X           int16
+	Y           int16
+	Z           int16
+	State       PeepState
+}
+
+// Calls fn with the position of every peep sprite of the given peep type.
+func (s *SaveState) eachPeep(peepType uint8, fn func(index int, pos uint32)) {
+	for index := 0; index < maxSprites; index++ {
+		pos := uint32(spritesPos + index*spriteSize)
+
+		if s.readUint8(pos) == peepIdentifier && s.readUint8(pos+0x2E) == peepType {
+			fn(index, pos)
+		}
+	}
+}
+
+func (s *SaveState) readPeep(index int, pos uint32) Peep {
+	return Peep{
+		SpriteIndex: index,
+		ID:          s.readUint32(pos + 0x9C),
+		Name:        s.userString(s.readUint16(pos + 0x22)),
+		X:           s.readInt16(pos + 0x0E),
+		Y:           s.readInt16(pos + 0x10),
+		Z:           s.readInt16(pos + 0x12),
+		State:       PeepState(s.readUint8(pos + 0x2B)),
+	}
+}
+
+// Returns the player-defined string for a string id, or an empty string if the
+// id refers to one of the game's built-in strings.
+func (s *SaveState) userString(id uint16) string {
+	if id < userStringsOffset {
+		return ""
+	}
+
+	pos := userStringsPos + uint32(id-userStringsOffset)*userStringSize
+	if pos+userStringSize > SaveStateSize {
+		return ""
+	}
+
+	str := s.readBytes(pos, userStringSize)
+	if end := bytes.IndexByte(str, 0); end >= 0 {
+		str = str[:end]
+	}
+
+	return string(str)
+}
@@ -1,14 +1,82 @@
 // Copyright (c) 2015, xrstf | MIT licensed
 
-//go:generate stringer -type=ResearchRate,ResearchFlag -output=research_strings.go
+//go:generate stringer -type=ResearchRate,ResearchFlag,ResearchItemType,ResearchStage -output=research_strings.go
 
 package sv4
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	researchItemsPos  = 0x199150
+	researchItemSize  = 5
+	maxResearchItems  = 200
+	lastResearchPos   = 0x19914C
+	nextResearchPos   = 0x199538
+	nextCategoryPos   = 0x19953E
+	researchStagePos  = 0x19914B
+	researchProgPos   = 0x19953C
+	endOfInvented     = 0xFF
+	endOfResearchable = 0xFE
+	endOfResearchList = 0xFD
+)
+
+// ResearchTask is an entry of the research list. Depending on the Type, Item is
+// a theme, a ride type or a vehicle type (for a ride type given by Ride).
 type ResearchTask struct {
 	Item     byte
 	Ride     byte
-	Category byte
+	Type     ResearchItemType
 	Flags    byte
+	Category ResearchFlag
+}
+
+type ResearchItemType byte
+
+const (
+	ThemeResearchItem ResearchItemType = iota
+	RideResearchItem
+	VehicleResearchItem
+	SpecialResearchItem
+)
+
+type ResearchStage uint8
+
+const (
+	InitialResearch ResearchStage = iota
+	DesigningResearch
+	CompletingResearch
+	UnknownResearch
+	FinishedAllResearch
+)
+
+var researchThemeNames = []string{
+	"General Themeing", "Mine Themeing", "Classical/Roman Themeing", "Egyptian Themeing",
+	"Martian Themeing", "Jumping Fountains", "Wonderland Themeing", "Jurassic Themeing",
+	"Spooky Themeing", "Jungle Themeing", "Abstract Themeing", "Garden Clock",
+	"Snow/Ice Themeing", "Medieval Themeing", "Space Themeing", "Creepy Themeing",
+	"Urban Themeing", "Pagoda Themeing",
+}
+
+// Returns a readable name for the researched item.
+func (t ResearchTask) Name() string {
+	switch t.Type {
+	case ThemeResearchItem:
+		if int(t.Item) < len(researchThemeNames) {
+			return researchThemeNames[t.Item]
+		}
+
+	case RideResearchItem:
+		return RideType(t.Item).String()
+
+	case VehicleResearchItem:
+		return RideType(t.Ride).String() + " vehicle " + strconv.Itoa(int(t.Item))
+	}
+
+	return fmt.Sprintf("%s %d", t.Type, t.Item)
 }
 
 type ResearchFlag byte
@@ -35,26 +103,179 @@ func (s *SaveState) ResearchRate() ResearchRate {
 	return ResearchRate(s.readUint8(0x198857))
 }
 
+func (s *SaveState) SetResearchRate(rate ResearchRate) error {
+	if rate > MaximumResearch {
+		return errors.New("Invalid research rate " + rate.String() + ".")
+	}
+
+	s.writeUint8(0x198857, uint8(rate))
+
+	return nil
+}
+
 func (s *SaveState) ResearchFlag(flag ResearchFlag) bool {
 	val := s.readByte(0x19914A)
 
 	return val&byte(flag) > 0
 }
 
-/*
-func (p *sv4parser) Parse() (*SaveState, error) {
-	save.ResearchProgress = p.consumeByte()
-	save.LastResearch = ResearchTask{
-		p.consumeByte(),
-		p.consumeByte(),
-		p.consumeByte(),
-		p.consumeByte(),
+func (s *SaveState) SetResearchFlag(flag ResearchFlag, enabled bool) {
+	val := s.readByte(0x19914A)
+
+	if enabled {
+		val |= byte(flag)
+	} else {
+		val &^= byte(flag)
+	}
+
+	s.writeByte(0x19914A, val)
+}
+
+func (s *SaveState) ResearchStage() ResearchStage {
+	return ResearchStage(s.readUint8(researchStagePos))
+}
+
+func (s *SaveState) SetResearchStage(stage ResearchStage) error {
+	if stage > FinishedAllResearch {
+		return errors.New("Invalid research stage " + stage.String() + ".")
+	}
+
+	s.writeUint8(researchStagePos, uint8(stage))
+
+	return nil
+}
+
+// Returns the progress on the current research item, ranging from 0 to 65535.
+func (s *SaveState) ResearchProgress() int {
+	return int(s.readUint16(researchProgPos))
+}
+
+func (s *SaveState) SetResearchProgress(progress int) error {
+	if err := checkRange("Research progress", progress, 0, 0xFFFF); err != nil {
+		return err
+	}
+
+	s.writeUint16(researchProgPos, uint16(progress))
+
+	return nil
+}
+
+// Returns the most recently invented item. Its Category is not stored.
+func (s *SaveState) LastResearch() ResearchTask {
+	return s.readResearchTask(lastResearchPos, 0)
+}
+
+func (s *SaveState) SetLastResearch(task ResearchTask) {
+	s.writeResearchTask(lastResearchPos, task)
+}
+
+// Returns the item currently being researched.
+func (s *SaveState) NextResearch() ResearchTask {
+	return s.readResearchTask(nextResearchPos, ResearchFlag(s.readUint8(nextCategoryPos)))
+}
+
+func (s *SaveState) SetNextResearch(task ResearchTask) {
+	s.writeResearchTask(nextResearchPos, task)
+	s.writeUint8(nextCategoryPos, uint8(task.Category))
+}
+
+// Returns the research list, split into already invented items and those that
+// are still to be researched.
+func (s *SaveState) ResearchItems() (invented []ResearchTask, researchable []ResearchTask) {
+	invented = make([]ResearchTask, 0)
+	researchable = make([]ResearchTask, 0)
+	target := &invented
+
+	for i := uint32(0); i < maxResearchItems; i++ {
+		pos := researchItemsPos + i*researchItemSize
+
+		switch s.readUint8(pos) {
+		case endOfInvented:
+			target = &researchable
+			continue
+
+		case endOfResearchable:
+			target = nil
+			continue
+
+		case endOfResearchList:
+			return
+		}
+
+		// items after the researchable list are not available in this scenario
+		if target != nil {
+			*target = append(*target, s.readResearchTask(pos, ResearchFlag(s.readUint8(pos+4))))
+		}
+	}
+
+	return
+}
+
+// Replaces the research list. Items that have been in the list, but are neither
+// invented nor researchable are kept after the researchable ones.
+func (s *SaveState) SetResearchItems(invented []ResearchTask, researchable []ResearchTask) error {
+	unavailable := make([]ResearchTask, 0)
+	afterEnd := false
+
+	for i := uint32(0); i < maxResearchItems && !afterEnd; i++ {
+		pos := researchItemsPos + i*researchItemSize
+
+		switch s.readUint8(pos) {
+		case endOfResearchable:
+			for j := i + 1; j < maxResearchItems; j++ {
+				pos = researchItemsPos + j*researchItemSize
+				if s.readUint8(pos) == endOfResearchList {
+					break
+				}
+
+				unavailable = append(unavailable, s.readResearchTask(pos, ResearchFlag(s.readUint8(pos+4))))
+			}
+
+			afterEnd = true
+
+		case endOfResearchList:
+			afterEnd = true
+		}
+	}
+
+	if total := len(invented) + len(researchable) + len(unavailable) + 3; total > maxResearchItems {
+		return fmt.Errorf("The research list can hold at most %d items including separators, got %d.", maxResearchItems, total)
 	}
 
-	save.NextResearch = ResearchTask{
-		p.consumeByte(),
-		p.consumeByte(),
-		p.consumeByte(),
-		p.consumeByte(),
+	pos := uint32(researchItemsPos)
+	separator := func(item byte) {
+		s.writeBytes(pos, []byte{item, 0xFF, 0xFF, 0xFF, 0xFF})
+		pos += researchItemSize
 	}
-*/
+
+	items := func(tasks []ResearchTask) {
+		for _, task := range tasks {
+			s.writeResearchTask(pos, task)
+			s.writeUint8(pos+4, uint8(task.Category))
+			pos += researchItemSize
+		}
+	}
+
+	items(invented)
+	separator(endOfInvented)
+	items(researchable)
+	separator(endOfResearchable)
+	items(unavailable)
+	separator(endOfResearchList)
+
+	return nil
+}
+
+func (s *SaveState) readResearchTask(pos uint32, category ResearchFlag) ResearchTask {
+	return ResearchTask{
+		Item:     s.readUint8(pos),
+		Ride:     s.readUint8(pos + 1),
+		Type:     ResearchItemType(s.readUint8(pos + 2)),
+		Flags:    s.readUint8(pos + 3),
+		Category: category,
+	}
+}
+
+func (s *SaveState) writeResearchTask(pos uint32, task ResearchTask) {
+	s.writeBytes(pos, []byte{task.Item, task.Ride, byte(task.Type), task.Flags})
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+//go:generate stringer -type=RideStatus -output=rides_strings.go
+
+package sv4
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	ridesPos                = 0x1A38A0
+	rideSize                = 0x260
+	maxRides                = 255
+	maxStations             = 4
+	maxTrains               = 12
+	emptyRideSlot           = 0xFF
+	rideBrokenDown          = 1 << 7
+	rideIndestructibleTrack = 1 << 14
+	undefinedRating         = 0xFFFF
+	undefinedTileLoc        = 0xFFFF
+)
+
+type RideType uint8
+
+var rideTypeNames = []string{
+	"Wooden Roller Coaster", "Stand-up Steel Roller Coaster", "Suspended Roller Coaster",
+	"Inverted Roller Coaster", "Steel Mini Roller Coaster", "Miniature Railway", "Monorail",
+	"Suspended Single Rail Roller Coaster", "Boat Hire", "Wooden Crazy Rodent Roller Coaster",
+	"Single Rail Roller Coaster", "Car Ride", "Launched Freefall", "Bobsled Roller Coaster",
+	"Observation Tower", "Steel Roller Coaster", "Water Slide", "Mine Train Roller Coaster",
+	"Chairlift", "Steel Corkscrew Roller Coaster", "Hedge Maze", "Spiral Slide", "Go Karts",
+	"Log Flume", "River Rapids", "Dodgems", "Swinging Ship", "Swinging Inverter Ship",
+	"Ice Cream Stall", "Chips Stall", "Drink Stall", "Candyfloss Stall", "Burger Bar",
+	"Merry-Go-Round", "Balloon Stall", "Information Kiosk", "Toilets", "Ferris Wheel",
+	"Motion Simulator", "3D Cinema", "Top Spin", "Space Rings", "Steel Reverse Freefall Roller Coaster",
+	"Souvenir Stall", "Vertical Roller Coaster", "Pizza Stall", "Twist", "Haunted House",
+	"Popcorn Stall", "Circus Show", "Ghost Train", "Steel Twister Roller Coaster",
+	"Wooden Twister Roller Coaster", "Wooden Side-Friction Roller Coaster",
+	"Steel Wild Mouse Roller Coaster", "Hot Dog Stall", "Exotic Sea Food Stall", "Hat Stall",
+	"Candy Apple Stand", "Virginia Reel", "River Ride", "Cycle Monorail", "Flying Roller Coaster",
+	"Suspended Monorail", "", "Wooden Reverser Roller Coaster", "Heartline Twister Roller Coaster",
+	"Miniature Golf", "", "Roto-Drop", "Flying Saucers", "Crooked House", "Cycle Railway",
+	"Suspended Looping Roller Coaster", "Water Coaster", "Air Powered Vertical Coaster",
+	"Inverted Wild Mouse Coaster", "Jet Skis", "T-Shirt Stall", "Raft Ride", "Doughnut Shop",
+	"Enterprise", "Coffee Shop", "Fried Chicken Stall", "Lemonade Stall",
+}
+
+func (t RideType) String() string {
+	if int(t) >= len(rideTypeNames) || rideTypeNames[t] == "" {
+		return fmt.Sprintf("RideType(%d)", t)
+	}
+
+	return rideTypeNames[t]
+}
+
+var coasterRideTypes = map[RideType]bool{
+	0: true, 1: true, 2: true, 3: true, 4: true, 7: true, 9: true, 10: true, 13: true,
+	15: true, 17: true, 19: true, 42: true, 44: true, 51: true, 52: true, 53: true,
+	54: true, 59: true, 62: true, 65: true, 66: true, 73: true, 74: true, 75: true, 76: true,
+}
+
+// Returns true for all kinds of roller coasters, as counted by scenario goals.
+func (t RideType) IsCoaster() bool {
+	return coasterRideTypes[t]
+}
+
+type RideStatus uint8
+
+const (
+	RideClosed RideStatus = iota
+	RideOpen
+	RideTesting
+	RideBrokenDown // not stored as a status, but derived from the ride's lifecycle flags
+)
+
+type TileLocation struct {
+	X uint8
+	Y uint8
+}
+
+type VehicleColor struct {
+	Body uint8
+	Trim uint8
+}
+
+type Ride struct {
+	Index       int
+	Type        RideType
+	VehicleType uint8
+	Name        string
+	Status      RideStatus
+
+	// ratings are -1 until the ride has been tested
+	Excitement float64
+	Intensity  float64
+	Nausea     float64
+
+	Reliability      uint8 // in percent
+	Downtime         uint8 // in percent
+	Price            int
+	CustomersPerHour int
+	TotalCustomers   int
+	IncomePerHour    int
+	Profit           int
+	TotalProfit      int
+	RunningCost      int
+	Age              int // in months
+	Length           int // in meters, summed over all stations
+
+	// PrebuiltTrack is set for rides the scenario came with and whose track
+	// cannot be demolished, like the unfinished coasters of a FiveCoasters goal.
+	PrebuiltTrack bool
+
+	Stations      int
+	Entrances     []TileLocation
+	Exits         []TileLocation
+	Trains        int
+	CarsPerTrain  int
+	TrackColors   [3]uint8 // main, additional, supports
+	VehicleColors []VehicleColor
+}
+
+// Returns all built rides, shops and stalls.
+func (s *SaveState) Rides() []Ride {
+	rides := make([]Ride, 0)
+
+	for index := 0; index < maxRides; index++ {
+		pos := uint32(ridesPos + index*rideSize)
+
+		if s.readUint8(pos) == emptyRideSlot {
+			continue
+		}
+
+		rides = append(rides, s.readRide(index, pos))
+	}
+
+	return rides
+}
+
+// see https://github.com/OpenRCT2/OpenRCT2/blob/develop/src/openrct2/rct1/RCT1.h
+func (s *SaveState) readRide(index int, pos uint32) Ride {
+	ride := Ride{
+		Index:            index,
+		Type:             RideType(s.readUint8(pos)),
+		VehicleType:      s.readUint8(pos + 0x01),
+		Status:           RideStatus(s.readUint8(pos + 0x21)),
+		Excitement:       s.readRating(pos + 0xF0),
+		Intensity:        s.readRating(pos + 0xF2),
+		Nausea:           s.readRating(pos + 0xF4),
+		Reliability:      s.readUint8(pos + 0x147),
+		Downtime:         s.readUint8(pos + 0x149),
+		Price:            int(s.readUint16(pos + 0xE8)),
+		CustomersPerHour: int(s.readInt16(pos + 0xD4)),
+		TotalCustomers:   int(s.readUint32(pos + 0x100)),
+		IncomePerHour:    int(s.readInt32(pos + 0x160)),
+		Profit:           int(s.readInt32(pos + 0x164)),
+		TotalProfit:      int(s.readInt32(pos + 0x104)),
+		RunningCost:      int(s.readInt16(pos + 0xE2)),
+		Age:              int(s.readUint16(pos + 0xE0)),
+		Stations:         int(s.readUint8(pos + 0x77)),
+		Entrances:        make([]TileLocation, 0, maxStations),
+		Exits:            make([]TileLocation, 0, maxStations),
+		Trains:           int(s.readUint8(pos + 0x78)),
+		CarsPerTrain:     int(s.readUint8(pos + 0x79)),
+		TrackColors:      [3]uint8{s.readUint8(pos + 0x1E), s.readUint8(pos + 0x1F), s.readUint8(pos + 0x20)},
+	}
+
+	lifecycle := s.readUint16(pos + 0x02)
+
+	if lifecycle&rideBrokenDown > 0 {
+		ride.Status = RideBrokenDown
+	}
+
+	ride.PrebuiltTrack = lifecycle&rideIndestructibleTrack > 0
+
+	name := s.readUint16(pos + 0x22)
+	if ride.Name = s.userString(name); ride.Name == "" {
+		ride.Name = ride.Type.String() + " " + strconv.Itoa(int(s.readUint16(pos+0x26)))
+	}
+
+	for station := uint32(0); station < maxStations; station++ {
+		if loc := s.readUint16(pos + 0x42 + station*2); loc != undefinedTileLoc {
+			ride.Entrances = append(ride.Entrances, TileLocation{uint8(loc), uint8(loc >> 8)})
+		}
+
+		if loc := s.readUint16(pos + 0x4A + station*2); loc != undefinedTileLoc {
+			ride.Exits = append(ride.Exits, TileLocation{uint8(loc), uint8(loc >> 8)})
+		}
+	}
+
+	for station := uint32(0); station < maxStations; station++ {
+		ride.Length += int(s.readInt32(pos+0x94+station*4) >> 16)
+	}
+
+	trains := ride.Trains
+	if trains > maxTrains {
+		trains = maxTrains
+	}
+
+	ride.VehicleColors = make([]VehicleColor, trains)
+	for train := range ride.VehicleColors {
+		colors := s.readBytes(pos+0x06+uint32(train)*2, 2)
+		ride.VehicleColors[train] = VehicleColor{colors[0], colors[1]}
+	}
+
+	return ride
+}
+
+func (s *SaveState) readRating(pos uint32) float64 {
+	rating := s.readUint16(pos)
+	if rating == undefinedRating {
+		return -1
+	}
+
+	return float64(rating) / 100
+}
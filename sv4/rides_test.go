@@ -0,0 +1,83 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+import "testing"
+
+// marks every ride slot as empty, as the game does for a new park
+func clearRides(state *SaveState) {
+	for index := 0; index < maxRides; index++ {
+		state.writeUint8(uint32(ridesPos+index*rideSize), emptyRideSlot)
+	}
+}
+
+func TestRides(t *testing.T) {
+	state := newTestSaveState(t)
+	clearRides(state)
+
+	if rides := state.Rides(); len(rides) != 0 {
+		t.Fatalf("Expected no rides, got %d.", len(rides))
+	}
+
+	// an unnamed, tested wooden roller coaster with two trains at index 2
+	pos := uint32(ridesPos + 2*rideSize)
+	state.writeUint8(pos, 0)
+	state.writeUint8(pos+0x01, 5)
+	state.writeUint8(pos+0x21, uint8(RideOpen))
+	state.writeUint16(pos+0x26, 3)
+	state.writeUint16(pos+0xF0, 652)
+	state.writeUint16(pos+0xF2, 480)
+	state.writeUint16(pos+0xF4, undefinedRating)
+	state.writeUint8(pos+0x147, 95)
+	state.writeUint16(pos+0xE8, 30)
+	state.writeInt32(pos+0x164, -1200)
+	state.writeUint16(pos+0xE0, 14)
+	state.writeUint8(pos+0x77, 1)
+	state.writeUint8(pos+0x78, 2)
+	state.writeUint8(pos+0x79, 6)
+	state.writeBytes(pos+0x06, []byte{1, 2, 3, 4})
+	state.writeBytes(pos+0x1E, []byte{7, 8, 9})
+
+	for station := uint32(0); station < maxStations; station++ {
+		state.writeUint16(pos+0x42+station*2, undefinedTileLoc)
+		state.writeUint16(pos+0x4A+station*2, undefinedTileLoc)
+	}
+
+	state.writeUint16(pos+0x42, 0x1005)
+	state.writeUint16(pos+0x4A, 0x1006)
+	state.writeInt32(pos+0x94, 250<<16|0x8000)
+
+	rides := state.Rides()
+	if len(rides) != 1 {
+		t.Fatalf("Expected 1 ride, got %d.", len(rides))
+	}
+
+	ride := rides[0]
+
+	if ride.Index != 2 || ride.Name != "Wooden Roller Coaster 3" || ride.VehicleType != 5 || ride.Status != RideOpen || !ride.Type.IsCoaster() {
+		t.Errorf("Ride was decoded wrongly: %+v", ride)
+	}
+
+	if ride.Excitement != 6.52 || ride.Intensity != 4.8 || ride.Nausea != -1 {
+		t.Errorf("Expected ratings 6.52/4.8/-1, got %v/%v/%v.", ride.Excitement, ride.Intensity, ride.Nausea)
+	}
+
+	if ride.Reliability != 95 || ride.Price != 30 || ride.Profit != -1200 || ride.Age != 14 || ride.Length != 250 {
+		t.Errorf("Ride statistics were decoded wrongly: %+v", ride)
+	}
+
+	if len(ride.Entrances) != 1 || ride.Entrances[0] != (TileLocation{5, 16}) || len(ride.Exits) != 1 || ride.Exits[0] != (TileLocation{6, 16}) {
+		t.Errorf("Expected one entrance at 5/16 and one exit at 6/16, got %v and %v.", ride.Entrances, ride.Exits)
+	}
+
+	if ride.TrackColors != [3]uint8{7, 8, 9} || len(ride.VehicleColors) != 2 || ride.VehicleColors[1] != (VehicleColor{3, 4}) {
+		t.Errorf("Colors were decoded wrongly: %v, %v", ride.TrackColors, ride.VehicleColors)
+	}
+
+	// breakdowns are stored in the lifecycle flags, not the status
+	state.writeUint16(pos+0x02, rideBrokenDown)
+
+	if status := state.Rides()[0].Status; status != RideBrokenDown {
+		t.Errorf("Expected the ride to be broken down, got %s.", status)
+	}
+}
@@ -1,6 +1,6 @@
 // Copyright (c) 2015, xrstf | MIT licensed
 
-//go:generate stringer -type=SaveStateType,scenarioGoalType,ParkFlag -output=savestate_strings.go
+//go:generate stringer -type=SaveStateType,ParkFlag -output=savestate_strings.go
 
 // Package sv4 implements an API to read/write savestates.
 //
@@ -16,9 +16,12 @@ package sv4
 
 import (
 	"errors"
+	"io"
+	"math"
 	"strconv"
 	"time"
 
+	"github.com/xrstf/rct/rle"
 	"github.com/xrstf/rct/utils"
 )
 
@@ -42,20 +45,6 @@ const (
 	TypeLL   SaveStateType = 120001
 )
 
-type scenarioGoalType uint8
-
-const (
-	GoalGuestsAndRating scenarioGoalType = iota
-	ParkValue
-	HaveFun
-	Competition
-	TenCoasters6Excitement
-	Maintain
-	MonthlyRideIncome
-	TenCoasters7Excitement
-	FiveCoasters
-)
-
 type ParkFlag uint32
 
 const (
@@ -75,19 +64,6 @@ const (
 	ProhibitToggleRealNames   ParkFlag = 0x8000
 )
 
-type scenarioGoal struct {
-	Type  scenarioGoalType
-	Years uint8
-
-	// set one of them, never both
-	MoneyGoal         uint32
-	CoasterExcitement uint32
-
-	// set one of them, never both
-	GuestGoal        uint16
-	MinCoasterLength uint16
-}
-
 // Creates a new SaveState from an uncompressed state file.
 //
 // Use the RLE decoder to uncompress before handing the raw data to this function.
@@ -104,6 +80,24 @@ func NewSaveState(state []byte) (*SaveState, error) {
 	return s, nil
 }
 
+// Writes the savestate the way the game stores it on disk: RLE encoded and
+// followed by the checksum for the given game type.
+func (s *SaveState) WriteTo(w io.Writer, gameType SaveStateType) (int64, error) {
+	encoded, err := rle.NewEncoder().Encode(s.data.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := w.Write(encoded)
+	if err != nil {
+		return int64(written), err
+	}
+
+	n, err := w.Write(Checksum(encoded, gameType))
+
+	return int64(written + n), err
+}
+
 // Returns the current year.
 func (s *SaveState) Year() int {
 	val := int(s.readUint16(0x000000))
@@ -125,27 +119,103 @@ func (s *SaveState) Days() int {
 	return (((ticks * days) >> 16) & 0xFF) + 1
 }
 
+// Sets the current date. The year starts at 1, months range from March to
+// October.
+func (s *SaveState) SetDate(year int, month time.Month, day int) error {
+	if err := checkRange("Year", year, 1, math.MaxUint16/8); err != nil {
+		return err
+	}
+
+	if err := checkRange("Month", int(month), int(time.March), int(time.October)); err != nil {
+		return err
+	}
+
+	days := DaysInMonth[month-3]
+
+	if err := checkRange("Day", day, 1, days); err != nil {
+		return err
+	}
+
+	// round up, so that Days() yields exactly the given day
+	ticks := ((day-1)<<16 + days - 1) / days
+
+	s.writeUint16(0x000000, uint16((year-1)*8+int(month)-3))
+	s.writeUint16(0x000002, uint16(ticks))
+
+	return nil
+}
+
 func (s *SaveState) ParkFlag(flag ParkFlag) bool {
 	val := s.readUint32(0x19883C)
 
 	return val&uint32(flag) > 0
 }
 
+func (s *SaveState) SetParkFlag(flag ParkFlag, enabled bool) {
+	val := s.readUint32(0x19883C)
+
+	if enabled {
+		val |= uint32(flag)
+	} else {
+		val &^= uint32(flag)
+	}
+
+	s.writeUint32(0x19883C, val)
+}
+
 func (s *SaveState) ParkRating() int {
 	return int(s.readUint16(0x199108))
 }
 
-// func (s *SaveState) ParkRatingHistory() []int {
-// 	return []int([]uint8(s.readBytes(0x19910A, 32)))
-// }
+func (s *SaveState) SetParkRating(rating int) error {
+	if err := checkRange("Park rating", rating, 0, 999); err != nil {
+		return err
+	}
+
+	s.writeUint16(0x199108, uint16(rating))
+
+	return nil
+}
+
+// Returns the last 32 recorded park ratings, oldest first. The game records
+// nothing while the park is closed.
+func (s *SaveState) ParkRatingHistory() []int {
+	return s.readByteHistory(0x19910A, 4)
+}
 
 func (s *SaveState) GuestCount() int {
 	return int(s.readUint16(0x198C9C))
 }
 
-// func (s *SaveState) GuestCountHistory() []int {
-// 	return []int([]uint8(s.readBytes(0x19912A, 32)))
-// }
+func (s *SaveState) SetGuestCount(count int) error {
+	if err := checkRange("Guest count", count, 0, math.MaxUint16); err != nil {
+		return err
+	}
+
+	s.writeUint16(0x198C9C, uint16(count))
+
+	return nil
+}
+
+// Returns the last 32 recorded guest counts, oldest first. The game only stores
+// the guest count in steps of 20.
+func (s *SaveState) GuestCountHistory() []int {
+	return s.readByteHistory(0x19912A, 20)
+}
+
+// The rating and guest history consist of 32 bytes each, most recent first.
+func (s *SaveState) readByteHistory(pos uint32, scale int) []int {
+	raw := s.readBytes(pos, 32)
+	history := make([]int, 0, len(raw))
+
+	for i := len(raw) - 1; i >= 0; i-- {
+		if raw[i] != 0xFF {
+			history = append(history, int(raw[i])*scale)
+		}
+	}
+
+	return history
+}
 
 func (s *SaveState) HandymenColor() byte {
 	return s.readByte(0x199025)
@@ -159,49 +229,28 @@ func (s *SaveState) SecurityGuardsColor() byte {
 	return s.readByte(0x199027)
 }
 
-/*
-func (p *sv4parser) Parse() (*SaveState, error) {
-	save.PocketCashBase = p.consumeInt16()
-	save.HungerBase = p.consumeUint8()
-	save.ThirstBase = p.consumeUint8()
-
-	// scenario goal
-	scGoal := p.consumeByte()
-
-	save.ScenarioGoal.Type = scenarioGoalType(scGoal)
-	save.ScenarioGoal.Years = p.consumeUint8()
+func (s *SaveState) SetHandymenColor(color byte) error {
+	return s.setColor(0x199025, color)
+}
 
-	p.consumeBytes(2)
+func (s *SaveState) SetMechanicsColor(color byte) error {
+	return s.setColor(0x199026, color)
+}
 
-	if save.ScenarioGoal.Type == FiveCoasters {
-		save.ScenarioGoal.CoasterExcitement = p.consumeUint32()
-	} else {
-		save.ScenarioGoal.MoneyGoal = p.consumeUint32()
-	}
+func (s *SaveState) SetSecurityGuardsColor(color byte) error {
+	return s.setColor(0x199027, color)
+}
 
-	if save.ScenarioGoal.Type == TenCoasters7Excitement {
-		save.ScenarioGoal.MinCoasterLength = p.consumeUint16()
-	} else {
-		save.ScenarioGoal.GuestGoal = p.consumeUint16()
+// the game knows 32 colors
+func (s *SaveState) setColor(pos uint32, color byte) error {
+	if err := checkRange("Color", int(color), 0, 31); err != nil {
+		return err
 	}
 
-	// 00 00 00 00 06 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 | 00000000ff00 | dynamite dunes, noch 6 wochen
-	// 82 00 00 00 06 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 | ff000000ff00 | + freier eintritt für 2 wochen
-	// 82 00 00 00 06 84 00 00 00 00 00 00 00 00 00 00 00 00 00 00 | ff000000ff0e | + minieisenbahn für 4 wochen
-	// 82 00 00 82 06 84 00 00 00 00 00 00 00 00 00 00 00 00 00 00 | ff000006ff0e | + free hamburger für 2 wochen
-	// 82 83 00 82 06 84 00 00 00 00 00 00 00 00 00 00 00 00 00 00 | ff180006ff0e | + free gokarts für 3 wochen
-
-	fmt.Printf("%x\n", p.consumeBytes(20))
-	fmt.Printf("%x\n", p.consumeBytes(6))
-
-	// ???
-	p.consumeBytes(16)
-
-	p.where()
+	s.writeByte(pos, color)
 
-	return save, nil
+	return nil
 }
-*/
 
 // see http://tid.rctspace.com/Checksum.html
 func Checksum(encodedSavestate []byte, gameType SaveStateType) []byte {
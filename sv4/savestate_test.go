@@ -0,0 +1,279 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xrstf/rct/rle"
+)
+
+func newTestSaveState(t *testing.T) *SaveState {
+	state, err := NewSaveState(make([]byte, SaveStateSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+func TestSettersAndWriteTo(t *testing.T) {
+	state := newTestSaveState(t)
+
+	if err := state.SetCash(-1500); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := state.SetDate(3, time.July, 17); err != nil {
+		t.Fatal(err)
+	}
+
+	state.SetParkFlag(ParkOpened, true)
+	state.SetParkFlag(NoMoneyMode, true)
+	state.SetParkFlag(ParkOpened, false)
+
+	if err := state.SetParkEntryFee(70000); err == nil {
+		t.Error("Setting an entry fee larger than 16 bits should fail.")
+	}
+
+	buf := &bytes.Buffer{}
+
+	if _, err := state.WriteTo(buf, TypeLL); err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := buf.Bytes()
+	checksum := encoded[len(encoded)-4:]
+
+	if expected := Checksum(encoded[:len(encoded)-4], TypeLL); !bytes.Equal(checksum, expected) {
+		t.Errorf("Checksum does not match: expected % X, got % X.", expected, checksum)
+	}
+
+	decoded, err := rle.NewDecoder().Decode(encoded[:len(encoded)-4])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = NewSaveState(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Cash() != -1500 {
+		t.Errorf("Expected cash to be -1500, got %d.", state.Cash())
+	}
+
+	if state.Year() != 3 || state.Month() != time.July || state.Days() != 17 {
+		t.Errorf("Expected the date to be July 17th, year 3, got %s %d, year %d.", state.Month(), state.Days(), state.Year())
+	}
+
+	if state.ParkFlag(ParkOpened) || !state.ParkFlag(NoMoneyMode) {
+		t.Error("Park flags were not written correctly.")
+	}
+}
+
+func TestResearchItems(t *testing.T) {
+	state := newTestSaveState(t)
+
+	invented := []ResearchTask{{Item: 0, Type: RideResearchItem, Category: RollercoastersResearch}}
+	researchable := []ResearchTask{
+		{Item: 3, Type: ThemeResearchItem, Category: ThemingResearch},
+		{Item: 2, Ride: 15, Type: VehicleResearchItem, Category: RollercoastersResearch},
+	}
+
+	if err := state.SetResearchItems(invented, researchable); err != nil {
+		t.Fatal(err)
+	}
+
+	gotInvented, gotResearchable := state.ResearchItems()
+
+	if len(gotInvented) != 1 || gotInvented[0] != invented[0] {
+		t.Errorf("Expected invented items %v, got %v.", invented, gotInvented)
+	}
+
+	if len(gotResearchable) != 2 || gotResearchable[1] != researchable[1] {
+		t.Errorf("Expected researchable items %v, got %v.", researchable, gotResearchable)
+	}
+
+	if name := gotResearchable[0].Name(); name != "Egyptian Themeing" {
+		t.Errorf("Expected theme name \"Egyptian Themeing\", got %q.", name)
+	}
+}
+
+func TestScenarioGoal(t *testing.T) {
+	state := newTestSaveState(t)
+	goal := ScenarioGoal{Type: GoalGuestsAndRating, Years: 3, GuestGoal: 1000}
+
+	if err := state.SetScenarioGoal(goal); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := state.ScenarioGoal(); got != goal {
+		t.Errorf("Expected goal %+v, got %+v.", goal, got)
+	}
+
+	state.SetGuestCount(1200)
+	state.SetParkRating(550)
+
+	if progress := state.GoalProgress(); progress.Achieved || progress.Progress() != 1 {
+		t.Errorf("Goal should not be achieved with a low park rating: %+v", progress)
+	}
+
+	state.SetParkRating(650)
+
+	if progress := state.GoalProgress(); !progress.Achieved {
+		t.Errorf("Goal should be achieved: %+v", progress)
+	}
+
+	goal = ScenarioGoal{Type: FiveCoasters, CoasterExcitement: 600}
+
+	if err := state.SetScenarioGoal(goal); err != nil {
+		t.Fatal(err)
+	}
+
+	// four finished scenario coasters, one that is still being tested and one
+	// the player built on their own
+	clearRides(state)
+
+	for index := 0; index < 6; index++ {
+		pos := uint32(ridesPos + index*rideSize)
+
+		state.writeUint8(pos, 0)
+		state.writeUint8(pos+0x21, uint8(RideOpen))
+		state.writeUint16(pos+0xF0, 650)
+
+		if index < 5 {
+			state.writeUint16(pos+0x02, rideIndestructibleTrack)
+		}
+	}
+
+	state.writeUint8(ridesPos+4*rideSize+0x21, uint8(RideTesting))
+
+	if progress := state.GoalProgress(); progress.Current != 4 || progress.Achieved {
+		t.Errorf("Only the four open scenario coasters should count: %+v", progress)
+	}
+
+	state.writeUint8(ridesPos+4*rideSize+0x21, uint8(RideOpen))
+
+	if progress := state.GoalProgress(); progress.Current != 5 || !progress.Achieved {
+		t.Errorf("Goal should be achieved with all five scenario coasters open: %+v", progress)
+	}
+}
+
+func TestFinanceReports(t *testing.T) {
+	state := newTestSaveState(t)
+
+	if err := state.SetDate(2, time.April, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	reports := state.FinanceReports()
+	for i := range reports {
+		reports[i].ParkTickets = 100
+		reports[i].StaffWages = -30
+	}
+
+	if err := state.SetFinanceReports(reports); err != nil {
+		t.Fatal(err)
+	}
+
+	reports = state.FinanceReports()
+
+	// index => year, month
+	expected := map[int][2]int{0: {2, 4}, 1: {2, 3}, 2: {1, 10}, 9: {1, 3}, 10: {0, 10}, 15: {0, 5}}
+	for i, e := range expected {
+		if r := reports[i]; r.Year != e[0] || int(r.Month) != e[1] {
+			t.Errorf("Expected report %d to be for %d/%d, got %d/%d.", i, e[0], e[1], r.Year, r.Month)
+		}
+	}
+
+	if profit := reports[0].Profit(); profit != 70 {
+		t.Errorf("Expected a profit of 70, got %d.", profit)
+	}
+
+	yearly := YearlyFinanceReports(reports)
+	if len(yearly) != 3 || yearly[2].Year != 2 || yearly[2].Income() != 200 || yearly[1].Income() != 800 {
+		t.Errorf("Yearly reports were aggregated wrongly: %+v", yearly)
+	}
+}
+
+func TestHistories(t *testing.T) {
+	state := newTestSaveState(t)
+
+	// the two most recent entries of each history, the rest is unused
+	guests := make([]byte, 32)
+	for i := range guests {
+		guests[i] = 0xFF
+	}
+
+	guests[0] = 12
+	guests[1] = 9
+	state.writeBytes(0x19912A, guests)
+
+	for i := uint32(0); i < 128; i++ {
+		state.writeInt32(0x199584+i*4, undefinedMoney)
+	}
+
+	state.writeInt32(0x199584, -500)
+	state.writeInt32(0x199584+4, 2000)
+
+	if history := state.GuestCountHistory(); !reflect.DeepEqual(history, []int{180, 240}) {
+		t.Errorf("Expected guest counts [180 240], got %v.", history)
+	}
+
+	if history := state.CashHistory(); !reflect.DeepEqual(history, []int{2000, -500}) {
+		t.Errorf("Expected cash values [2000 -500], got %v.", history)
+	}
+}
+
+func TestWriteFinanceReports(t *testing.T) {
+	reports := []FinanceReport{
+		{Year: 2, Month: time.April, ParkTickets: 100, StaffWages: -30},
+		{Year: 1, RideTickets: 50, Research: -20},
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := WriteFinanceReportsCSV(buf, reports); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "year,month,ride_construction,ride_operation,land_purchase,landscaping,park_tickets,ride_tickets,shop_sales,shop_stock,food_sales,food_stock,staff_wages,marketing,research,loan_interest,income,expenditure,profit\n" +
+		"2,4,0,0,0,0,100,0,0,0,0,0,-30,0,0,0,100,-30,70\n" +
+		"1,0,0,0,0,0,0,50,0,0,0,0,0,0,-20,0,50,-20,30\n"
+
+	if buf.String() != expected {
+		t.Errorf("Expected CSV\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+
+	if err := WriteFinanceReportsJSON(buf, reports); err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := buf.Bytes()
+	decoded := make([]FinanceReport, 0)
+
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded, reports) {
+		t.Errorf("Reports did not survive a JSON round trip: %+v", decoded)
+	}
+
+	totals := make([]map[string]int, 0)
+
+	if err := json.Unmarshal(encoded, &totals); err != nil {
+		t.Fatal(err)
+	}
+
+	if totals[1]["income"] != 50 || totals[1]["expenditure"] != -20 || totals[1]["profit"] != 30 {
+		t.Errorf("Expected the totals to be included, got %v.", totals[1])
+	}
+}
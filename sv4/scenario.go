@@ -0,0 +1,238 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+//go:generate stringer -type=ScenarioGoalType -output=scenario_strings.go
+
+package sv4
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	goalTypePos   = 0x199550
+	goalYearsPos  = 0x199551
+	goalMoneyPos  = 0x199554
+	goalGuestsPos = 0x199558
+)
+
+type ScenarioGoalType uint8
+
+const (
+	GoalGuestsAndRating ScenarioGoalType = iota
+	ParkValue
+	HaveFun
+	Competition
+	TenCoasters6Excitement
+	Maintain
+	MonthlyRideIncome
+	TenCoasters7Excitement
+	FiveCoasters
+	RepayLoanAndParkValue
+	MonthlyFoodIncome
+)
+
+// minimum park rating for the guest goals
+const (
+	guestGoalRating    = 600
+	maintainGoalRating = 700
+)
+
+type ScenarioGoal struct {
+	Type  ScenarioGoalType
+	Years uint8 // the goal must be reached by the end of this year
+
+	// set one of them, never both
+	MoneyGoal         uint32
+	CoasterExcitement uint32 // in hundredths, only for FiveCoasters
+
+	// set one of them, never both
+	GuestGoal        uint16
+	MinCoasterLength uint16 // in meters, only for TenCoasters7Excitement
+}
+
+// Returns a description like the one shown in the game's scenario window.
+func (g ScenarioGoal) String() string {
+	switch g.Type {
+	case GoalGuestsAndRating:
+		return fmt.Sprintf("Have at least %d guests in your park at the end of year %d, with a park rating of at least %d.", g.GuestGoal, g.Years, guestGoalRating)
+	case ParkValue:
+		return fmt.Sprintf("Have a park value of at least %s at the end of year %d.", formatMoney(int(g.MoneyGoal)), g.Years)
+	case HaveFun:
+		return "Have fun!"
+	case Competition:
+		return "Build the best ride you can."
+	case TenCoasters6Excitement:
+		return "Have 10 different types of roller coasters operating in your park, each with an excitement rating of at least 6.00."
+	case Maintain:
+		return fmt.Sprintf("Have at least %d guests in your park. You must not let the park rating drop below %d at any time.", g.GuestGoal, maintainGoalRating)
+	case MonthlyRideIncome:
+		return fmt.Sprintf("Achieve a monthly income from ride tickets of at least %s.", formatMoney(int(g.MoneyGoal)))
+	case TenCoasters7Excitement:
+		return fmt.Sprintf("Have 10 different types of roller coasters operating in your park, each with a minimum length of %dm and an excitement rating of at least 7.00.", g.MinCoasterLength)
+	case FiveCoasters:
+		return fmt.Sprintf("Finish building all 5 of the partially built roller coasters in this park, designing them to achieve excitement ratings of at least %.2f each.", float64(g.CoasterExcitement)/100)
+	case RepayLoanAndParkValue:
+		return fmt.Sprintf("Repay your loan and achieve a park value of at least %s.", formatMoney(int(g.MoneyGoal)))
+	case MonthlyFoodIncome:
+		return fmt.Sprintf("Achieve a monthly profit from food, drink and merchandise sales of at least %s.", formatMoney(int(g.MoneyGoal)))
+	}
+
+	return g.Type.String()
+}
+
+func (s *SaveState) ScenarioGoal() ScenarioGoal {
+	goal := ScenarioGoal{
+		Type:  ScenarioGoalType(s.readUint8(goalTypePos)),
+		Years: s.readUint8(goalYearsPos),
+	}
+
+	if goal.Type == FiveCoasters {
+		goal.CoasterExcitement = s.readUint32(goalMoneyPos)
+	} else {
+		goal.MoneyGoal = s.readUint32(goalMoneyPos)
+	}
+
+	if goal.Type == TenCoasters7Excitement {
+		goal.MinCoasterLength = s.readUint16(goalGuestsPos)
+	} else {
+		goal.GuestGoal = s.readUint16(goalGuestsPos)
+	}
+
+	return goal
+}
+
+func (s *SaveState) SetScenarioGoal(goal ScenarioGoal) error {
+	if goal.Type > MonthlyFoodIncome {
+		return errors.New("Invalid scenario goal type " + goal.Type.String() + ".")
+	}
+
+	money := goal.MoneyGoal
+	if goal.Type == FiveCoasters {
+		money = goal.CoasterExcitement
+	}
+
+	if money > math.MaxInt32 {
+		return errors.New("The scenario's money goal must fit into 31 bits.")
+	}
+
+	guests := goal.GuestGoal
+	if goal.Type == TenCoasters7Excitement {
+		guests = goal.MinCoasterLength
+	}
+
+	s.writeUint8(goalTypePos, uint8(goal.Type))
+	s.writeUint8(goalYearsPos, goal.Years)
+	s.writeUint32(goalMoneyPos, money)
+	s.writeUint16(goalGuestsPos, guests)
+
+	return nil
+}
+
+// GoalProgress compares the current state of a park to its scenario goal.
+// Current and Target are given in the goal's unit (guests, money, coasters).
+type GoalProgress struct {
+	Goal           ScenarioGoal
+	Current        int
+	Target         int
+	Achieved       bool
+	DeadlinePassed bool
+}
+
+// Returns the progress between 0 and 1. Goals that cannot be measured, like
+// HaveFun, always have a progress of 0.
+func (p GoalProgress) Progress() float64 {
+	if p.Target <= 0 {
+		return 0
+	}
+
+	return math.Min(1, math.Max(0, float64(p.Current)/float64(p.Target)))
+}
+
+// Evaluates the scenario goal against the current state. Goals that are only
+// checked at the end of a month are evaluated against the last full month.
+func (s *SaveState) GoalProgress() GoalProgress {
+	goal := s.ScenarioGoal()
+	progress := GoalProgress{Goal: goal}
+
+	switch goal.Type {
+	case GoalGuestsAndRating, Maintain:
+		minRating := guestGoalRating
+		if goal.Type == Maintain {
+			minRating = maintainGoalRating
+		}
+
+		progress.Current = s.GuestCount()
+		progress.Target = int(goal.GuestGoal)
+		progress.Achieved = progress.Current >= progress.Target && s.ParkRating() >= minRating
+
+	case ParkValue, RepayLoanAndParkValue:
+		progress.Current = s.ParkValue()
+		progress.Target = int(goal.MoneyGoal)
+		progress.Achieved = progress.Current >= progress.Target
+
+		if goal.Type == RepayLoanAndParkValue {
+			progress.Achieved = progress.Achieved && s.Loan() == 0
+		}
+
+	case MonthlyRideIncome, MonthlyFoodIncome:
+		lastMonth := s.FinanceReports()[1]
+
+		if goal.Type == MonthlyRideIncome {
+			progress.Current = int(lastMonth.RideTickets)
+		} else {
+			progress.Current = int(lastMonth.ShopSales + lastMonth.ShopStock + lastMonth.FoodSales + lastMonth.FoodStock)
+		}
+
+		progress.Target = int(goal.MoneyGoal)
+		progress.Achieved = progress.Current >= progress.Target
+
+	case TenCoasters6Excitement, TenCoasters7Excitement:
+		minExcitement := 6.0
+		minLength := 0
+
+		if goal.Type == TenCoasters7Excitement {
+			minExcitement = 7.0
+			minLength = int(goal.MinCoasterLength)
+		}
+
+		types := make(map[RideType]bool)
+
+		for _, ride := range s.Rides() {
+			if ride.Type.IsCoaster() && ride.Status != RideClosed && ride.Status != RideTesting && ride.Excitement >= minExcitement && ride.Length >= minLength {
+				types[ride.Type] = true
+			}
+		}
+
+		progress.Current = len(types)
+		progress.Target = 10
+		progress.Achieved = progress.Current >= progress.Target
+
+	case FiveCoasters:
+		minExcitement := float64(goal.CoasterExcitement) / 100
+
+		// only the partially built coasters the scenario came with count; the
+		// game marks them by making their track indestructible
+		for _, ride := range s.Rides() {
+			if ride.PrebuiltTrack && ride.Type.IsCoaster() && ride.Status != RideClosed && ride.Status != RideTesting && ride.Excitement >= minExcitement {
+				progress.Current++
+			}
+		}
+
+		progress.Target = 5
+		progress.Achieved = progress.Current >= progress.Target
+	}
+
+	// only these goals have to be reached by a given year
+	if goal.Type == GoalGuestsAndRating || goal.Type == ParkValue {
+		progress.DeadlinePassed = s.Year() > int(goal.Years)
+	}
+
+	return progress
+}
+
+// money is stored in tenths of the currency unit
+func formatMoney(value int) string {
+	return fmt.Sprintf("%.2f", float64(value)/10)
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+//go:generate stringer -type=StaffType,Costume -output=staff_strings.go
+
+package sv4
+
+const (
+	patrolAreasPos  = 0x1F06AA
+	patrolAreaSize  = 128
+	maxStaff        = 116
+	patrolBlockSize = 4 // patrol areas are defined in blocks of 4x4 tiles
+)
+
+type StaffType uint8
+
+const (
+	Handyman StaffType = iota
+	Mechanic
+	SecurityGuard
+	Entertainer
+)
+
+var staffWages = map[StaffType]int{
+	Handyman:      500,
+	Mechanic:      800,
+	SecurityGuard: 600,
+	Entertainer:   550,
+}
+
+// Returns the monthly wages, in the same unit as Cash(). The game does not store
+// wages per staff member.
+func (t StaffType) Wages() int {
+	return staffWages[t]
+}
+
+// Costume is the outfit of an entertainer.
+type Costume uint8
+
+const (
+	PandaCostume Costume = iota
+	TigerCostume
+	ElephantCostume
+	RomanCostume
+	GorillaCostume
+	SnowmanCostume
+	KnightCostume
+	AstronautCostume
+)
+
+// NoCostume is used for staff members that are not entertainers.
+const NoCostume Costume = 0xFF
+
+// the game stores the peep's sprite type, which lists the costumes after the
+// sprites for guests, handymen, mechanics and security guards
+const firstCostumeSprite = 4
+
+// StaffOrders is a bitfield; the meaning of each bit depends on the staff type.
+type StaffOrders uint8
+
+const (
+	SweepFootpaths StaffOrders = 0x01
+	WaterGardens   StaffOrders = 0x02
+	EmptyBins      StaffOrders = 0x04
+	MowGrass       StaffOrders = 0x08
+
+	InspectRides StaffOrders = 0x01
+	FixRides     StaffOrders = 0x02
+)
+
+// PatrolArea has one bit per block of 4x4 tiles. An empty patrol area means the
+// staff member can walk anywhere.
+type PatrolArea [patrolAreaSize]byte
+
+func (a PatrolArea) Contains(x int, y int) bool {
+	if x < 0 || y < 0 || x >= MapSize || y >= MapSize {
+		return false
+	}
+
+	bit := (y/patrolBlockSize)<<5 | (x / patrolBlockSize)
+
+	return a[bit/8]&(1<<uint(bit%8)) > 0
+}
+
+func (a PatrolArea) IsEmpty() bool {
+	for _, b := range a {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+type StaffMember struct {
+	Peep
+	StaffID    uint8
+	Type       StaffType
+	Orders     StaffOrders
+	Costume    Costume // NoCostume unless this is an entertainer
+	Wages      int
+	PatrolArea PatrolArea
+
+	// handymen
+	LawnsMown      int
+	GardensWatered int
+	LitterSwept    int
+	BinsEmptied    int
+
+	// mechanics
+	RidesFixed     int
+	RidesInspected int
+}
+
+func (m *StaffMember) HasOrder(order StaffOrders) bool {
+	return m.Orders&order > 0
+}
+
+// Returns all employed staff members.
+func (s *SaveState) Staff() []StaffMember {
+	staff := make([]StaffMember, 0)
+
+	s.eachPeep(staffPeep, func(index int, pos uint32) {
+		member := StaffMember{
+			Peep:    s.readPeep(index, pos),
+			StaffID: s.readUint8(pos + 0xC5),
+			Type:    StaffType(s.readUint8(pos + 0x2F)),
+			Orders:  StaffOrders(s.readUint8(pos + 0xC6)),
+			Costume: NoCostume,
+		}
+
+		if sprite := s.readUint8(pos + 0x2D); member.Type == Entertainer && sprite >= firstCostumeSprite {
+			member.Costume = Costume(sprite - firstCostumeSprite)
+		}
+
+		member.Wages = member.Type.Wages()
+
+		if member.StaffID < maxStaff {
+			copy(member.PatrolArea[:], s.readBytes(patrolAreasPos+uint32(member.StaffID)*patrolAreaSize, patrolAreaSize))
+		}
+
+		first := int(s.readUint16(pos + 0xE4))
+		second := int(s.readUint16(pos + 0xE6))
+
+		switch member.Type {
+		case Handyman:
+			member.LawnsMown = first
+			member.GardensWatered = second
+			member.LitterSwept = int(s.readUint16(pos + 0xE8))
+			member.BinsEmptied = int(s.readUint16(pos + 0xEA))
+
+		case Mechanic:
+			member.RidesFixed = first
+			member.RidesInspected = second
+		}
+
+		staff = append(staff, member)
+	})
+
+	return staff
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015, xrstf | MIT licensed
+
+package sv4
+
+import "testing"
+
+func TestStaff(t *testing.T) {
+	state := newTestSaveState(t)
+
+	// a guest and a handyman with staff ID 3, patrolling the 4x4 tiles at (4,8)
+	guestPos := uint32(spritesPos + 1*spriteSize)
+	state.writeUint8(guestPos, peepIdentifier)
+	state.writeUint8(guestPos+0x2E, guestPeep)
+
+	pos := uint32(spritesPos + 9*spriteSize)
+	state.writeUint8(pos, peepIdentifier)
+	state.writeUint8(pos+0x2E, staffPeep)
+	state.writeUint8(pos+0x2F, uint8(Handyman))
+	state.writeUint8(pos+0x2B, uint8(PeepSweeping))
+	state.writeUint8(pos+0xC5, 3)
+	state.writeUint8(pos+0xC6, uint8(SweepFootpaths|EmptyBins))
+	state.writeUint16(pos+0xE4, 11)
+	state.writeUint16(pos+0xE6, 12)
+	state.writeUint16(pos+0xE8, 13)
+	state.writeUint16(pos+0xEA, 14)
+
+	// block (1,2) is bit 2<<5|1 = 65, i.e. bit 1 of byte 8
+	state.writeUint8(patrolAreasPos+3*patrolAreaSize+8, 0x02)
+
+	staff := state.Staff()
+	if len(staff) != 1 {
+		t.Fatalf("Expected 1 staff member, got %d.", len(staff))
+	}
+
+	member := staff[0]
+
+	if member.SpriteIndex != 9 || member.State != PeepSweeping || member.StaffID != 3 || member.Type != Handyman {
+		t.Errorf("Staff member was decoded wrongly: %+v", member)
+	}
+
+	if !member.HasOrder(EmptyBins) || member.HasOrder(MowGrass) {
+		t.Errorf("Orders were decoded wrongly: %b", member.Orders)
+	}
+
+	if member.LawnsMown != 11 || member.GardensWatered != 12 || member.LitterSwept != 13 || member.BinsEmptied != 14 {
+		t.Errorf("Handyman stats were decoded wrongly: %+v", member)
+	}
+
+	if member.Costume != NoCostume {
+		t.Errorf("Expected a handyman to wear no costume, got %s.", member.Costume)
+	}
+
+	if member.Wages != Handyman.Wages() || member.Wages != 500 {
+		t.Errorf("Expected wages of 500, got %d.", member.Wages)
+	}
+
+	if member.PatrolArea.IsEmpty() || !member.PatrolArea.Contains(7, 11) || member.PatrolArea.Contains(8, 11) {
+		t.Error("Patrol area was decoded wrongly.")
+	}
+
+	// the same stats mean something else for mechanics
+	state.writeUint8(pos+0x2F, uint8(Mechanic))
+
+	member = state.Staff()[0]
+	if member.RidesFixed != 11 || member.RidesInspected != 12 || member.LitterSwept != 0 {
+		t.Errorf("Mechanic stats were decoded wrongly: %+v", member)
+	}
+
+	// an entertainer in the knight costume (sprite type 10)
+	state.writeUint8(pos+0x2F, uint8(Entertainer))
+	state.writeUint8(pos+0x2D, 10)
+
+	if costume := state.Staff()[0].Costume; costume != KnightCostume {
+		t.Errorf("Expected the knight costume, got %s.", costume)
+	}
+}
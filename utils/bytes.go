@@ -132,3 +132,38 @@ func (self *ByteSlice) ConsumeInt32() int32 {
 	defer self.advance(4)
 	return self.ReadInt32(self.cursor)
 }
+
+// Returns the underlying byte slice. Changes to it are reflected in the ByteSlice.
+func (self *ByteSlice) Bytes() []byte {
+	return self.data
+}
+
+// Overwrites the bytes starting (including) at pos.
+func (self *ByteSlice) WriteBytes(pos uint32, b []byte) {
+	copy(self.data[pos:(pos+uint32(len(b)))], b)
+}
+
+// Write an unsigned 8-bit integer at pos.
+func (self *ByteSlice) WriteUint8(pos uint32, value uint8) {
+	self.data[pos] = byte(value)
+}
+
+// Write an unsigned 16-bit integer at pos.
+func (self *ByteSlice) WriteUint16(pos uint32, value uint16) {
+	binary.LittleEndian.PutUint16(self.data[pos:(pos+2)], value)
+}
+
+// Write a signed 16-bit integer at pos.
+func (self *ByteSlice) WriteInt16(pos uint32, value int16) {
+	self.WriteUint16(pos, uint16(value))
+}
+
+// Write an unsigned 32-bit integer at pos.
+func (self *ByteSlice) WriteUint32(pos uint32, value uint32) {
+	binary.LittleEndian.PutUint32(self.data[pos:(pos+4)], value)
+}
+
+// Write a signed 32-bit integer at pos.
+func (self *ByteSlice) WriteInt32(pos uint32, value int32) {
+	self.WriteUint32(pos, uint32(value))
+}